@@ -0,0 +1,474 @@
+package gson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+)
+
+// FlattenArray returns a pointer to a new `Gson` array in which nested
+// arrays have been collapsed into their parent up to `depth` levels
+// (a negative `depth` flattens completely). Non-array elements are kept as-is.
+//
+//	js.Get("pages").FlattenArray(1)
+func (self *Gson) FlattenArray(depth int) (*Gson, error) {
+	a, err := self.Array()
+	if err != nil {
+		return nil, err
+	}
+	return &Gson{data: flattenArray(make([]interface{}, 0, len(a)), a, depth)}, nil
+}
+
+func flattenArray(dst, src []interface{}, depth int) []interface{} {
+	for i := range src {
+		v := expandElem(src, i)
+		if sub, ok := v.([]interface{}); ok && depth != 0 {
+			dst = flattenArray(dst, sub, depth-1)
+			continue
+		}
+		dst = append(dst, v)
+	}
+	return dst
+}
+
+// GetIndexRange returns the elements of its `array` representation from
+// `start` up to (but excluding) `end`, each wrapped in a `Gson` object.
+// Out of range bounds are clamped. A non-array, or a window selecting
+// nothing, yields an empty (non-nil) slice.
+//
+// useful for rendering a page of results:
+//
+//	for _, item := range js.Get("items").GetIndexRange(20, 30) {
+//		fmt.Println(item.Get("title").MustString())
+//	}
+func (self *Gson) GetIndexRange(start, end int) []*Gson {
+	a, err := self.Array()
+	if err != nil {
+		return []*Gson{}
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end > len(a) {
+		end = len(a)
+	}
+	if start >= end {
+		return []*Gson{}
+	}
+	out := make([]*Gson, 0, end-start)
+	for i := start; i < end; i++ {
+		out = append(out, self.child(expandElem(a, i)))
+	}
+	return out
+}
+
+// UnionKeys returns the sorted union of the keys of every object in its
+// `array` representation. Elements that are not objects are skipped, or
+// make UnionKeys fail when `strict` is true.
+//
+// useful for computing the columns of a ragged array of records:
+//
+//	header, err := js.Get("rows").UnionKeys()
+func (self *Gson) UnionKeys(strict ...bool) ([]string, error) {
+	var failOnOther bool
+
+	switch len(strict) {
+	case 0:
+	case 1:
+		failOnOther = strict[0]
+	default:
+		log.Panicf("UnionKeys() received too many arguments %d", len(strict))
+	}
+
+	a, err := self.Array()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]interface{})
+	for i := range a {
+		m, ok := expandElem(a, i).(map[string]interface{})
+		if !ok {
+			if failOnOther {
+				return nil, fmt.Errorf("element %d is not an object", i)
+			}
+			continue
+		}
+		for k := range m {
+			seen[k] = nil
+		}
+	}
+	return sortedKeys(seen), nil
+}
+
+// NormalizeKeys returns a pointer to a new `Gson` array in which every
+// object of its `array` representation has the full set of keys found by
+// UnionKeys, absent ones set to a copy of `fill`. Elements that are not
+// objects are kept as-is, and the original array is left untouched.
+//
+//	table, err := js.Get("rows").NormalizeKeys(nil)
+func (self *Gson) NormalizeKeys(fill interface{}) (*Gson, error) {
+	keys, err := self.UnionKeys()
+	if err != nil {
+		return nil, err
+	}
+	a := self.MustArray()
+	out := make([]interface{}, len(a))
+	for i, v := range a {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			out[i] = v
+			continue
+		}
+		n := make(map[string]interface{}, len(keys))
+		for _, k := range keys {
+			if e, ok := m[k]; ok {
+				n[k] = e
+			} else {
+				n[k] = deepCopy(fill)
+			}
+		}
+		out[i] = n
+	}
+	return &Gson{data: out}, nil
+}
+
+// Sample returns a pointer to a new `Gson` array holding up to `n` elements
+// of its `array` representation, picked at random with the math/rand
+// global source. The picked elements keep their original relative order;
+// an array of `n` or fewer elements is returned whole.
+func (self *Gson) Sample(n int) (*Gson, error) {
+	return self.SampleWithRand(n, nil)
+}
+
+// SampleWithRand is like Sample but draws from `rng`, so that a seeded
+// source gives reproducible samples:
+//
+//	fixture, err := js.Get("rows").SampleWithRand(50, rand.New(rand.NewSource(1)))
+func (self *Gson) SampleWithRand(n int, rng *rand.Rand) (*Gson, error) {
+	a, err := self.Array()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, errors.New("sample size must not be negative")
+	}
+	if n >= len(a) {
+		return &Gson{data: append([]interface{}{}, a...)}, nil
+	}
+	perm := rand.Perm
+	if rng != nil {
+		perm = rng.Perm
+	}
+	picked := perm(len(a))[:n]
+	sort.Ints(picked)
+	out := make([]interface{}, n)
+	for i, idx := range picked {
+		out[i] = a[idx]
+	}
+	return &Gson{data: out}, nil
+}
+
+// Chunk returns a pointer to a new `Gson` array of arrays, each holding
+// `size` consecutive elements of its `array` representation; the last
+// chunk holds whatever remains. An empty array yields no chunks.
+//
+//	batches, err := js.Get("ids").Chunk(100)
+func (self *Gson) Chunk(size int) (*Gson, error) {
+	a, err := self.Array()
+	if err != nil {
+		return nil, err
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("chunk size must be positive, got %d", size)
+	}
+	out := make([]interface{}, 0, (len(a)+size-1)/size)
+	for start := 0; start < len(a); start += size {
+		end := start + size
+		if end > len(a) {
+			end = len(a)
+		}
+		out = append(out, append([]interface{}{}, a[start:end]...))
+	}
+	return &Gson{data: out}, nil
+}
+
+// Reverse returns a pointer to a new `Gson` array holding the elements of
+// its `array` representation in reverse order. The original array is left
+// untouched.
+func (self *Gson) Reverse() (*Gson, error) {
+	a, err := self.Array()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]interface{}, len(a))
+	for i, v := range a {
+		out[len(a)-1-i] = v
+	}
+	return &Gson{data: out}, nil
+}
+
+// Contains reports whether any element of its `array` representation is
+// deep-equal to `value` once reduced to its JSON form. Numbers compare by
+// value, so an element parsed as json.Number("3") matches int 3:
+//
+//	ok, err := js.Get("roles").Contains("admin")
+func (self *Gson) Contains(value interface{}) (bool, error) {
+	i, err := self.IndexOf(value)
+	return i >= 0, err
+}
+
+// IndexOf returns the index of the first element of its `array`
+// representation deep-equal to `value`, compared as in Contains, or -1 if
+// there is none
+func (self *Gson) IndexOf(value interface{}) (int, error) {
+	a, err := self.Array()
+	if err != nil {
+		return -1, err
+	}
+	v, err := jsonValue(value)
+	if err != nil {
+		return -1, err
+	}
+	return indexOf(a, v), nil
+}
+
+// IndexedGson pairs an element of an array with its position, as returned
+// by Enumerate
+type IndexedGson struct {
+	Index int
+	Value *Gson
+}
+
+// Enumerate returns every element of its `array` representation wrapped in
+// a `Gson` object alongside its index. Like GetIndex, the wrapped values
+// share nested objects and arrays with the document.
+//
+// useful for sorting or filtering while keeping track of positions:
+//
+//	items, err := js.Get("items").Enumerate()
+//	sort.Slice(items, func(i, j int) bool {
+//		return items[i].Value.Get("rank").MustInt() < items[j].Value.Get("rank").MustInt()
+//	})
+func (self *Gson) Enumerate() ([]IndexedGson, error) {
+	a, err := self.Array()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]IndexedGson, len(a))
+	for i := range a {
+		out[i] = IndexedGson{Index: i, Value: self.child(expandElem(a, i))}
+	}
+	return out, nil
+}
+
+// ArrayDiff returns a pointer to a new `Gson` array holding the elements of
+// its `array` representation that are not in `other`, compared as in
+// Contains. Like ArrayIntersect and ArrayUnion it treats both arrays as
+// sets: each distinct element appears once, in order of first appearance,
+// and the result shares nothing with either input.
+//
+// useful for reconciliation:
+//
+//	toAdd, _ := desired.ArrayDiff(current)
+//	toRemove, _ := current.ArrayDiff(desired)
+func (self *Gson) ArrayDiff(other *Gson) (*Gson, error) {
+	return self.arraySet(other, func(inOther bool) bool { return !inOther }, false)
+}
+
+// ArrayIntersect returns a pointer to a new `Gson` array holding the
+// elements of its `array` representation that are also in `other`, with
+// the set semantics of ArrayDiff
+func (self *Gson) ArrayIntersect(other *Gson) (*Gson, error) {
+	return self.arraySet(other, func(inOther bool) bool { return inOther }, false)
+}
+
+// ArrayUnion returns a pointer to a new `Gson` array holding the elements
+// of its `array` representation followed by those of `other` it lacks,
+// with the set semantics of ArrayDiff
+func (self *Gson) ArrayUnion(other *Gson) (*Gson, error) {
+	return self.arraySet(other, func(bool) bool { return true }, true)
+}
+
+// arraySet keeps the distinct elements of self for which `keep` returns
+// true given their membership in `other`, then appends the remaining
+// distinct elements of `other` if `appendOther` is set
+func (self *Gson) arraySet(other *Gson, keep func(inOther bool) bool, appendOther bool) (*Gson, error) {
+	if _, err := self.Array(); err != nil {
+		return nil, err
+	}
+	if _, err := other.Array(); err != nil {
+		return nil, err
+	}
+	v, err := jsonValue(self.data)
+	if err != nil {
+		return nil, err
+	}
+	w, err := jsonValue(other.data)
+	if err != nil {
+		return nil, err
+	}
+	a, b := v.([]interface{}), w.([]interface{})
+
+	out := make([]interface{}, 0, len(a))
+	for _, e := range a {
+		if indexOf(out, e) < 0 && keep(indexOf(b, e) >= 0) {
+			out = append(out, e)
+		}
+	}
+	if appendOther {
+		for _, e := range b {
+			if indexOf(out, e) < 0 {
+				out = append(out, e)
+			}
+		}
+	}
+	return &Gson{data: out}, nil
+}
+
+// CountMissing is the CountValues bucket for elements lacking the key
+const CountMissing = "(missing)"
+
+// CountValues tallies, over the objects of its `array` representation, how
+// many hold each distinct value of `key`. Values are bucketed by their
+// AsString form, except null, which is counted as "null" so that it stays
+// apart from the empty string. Elements without the key, including ones
+// that are not objects, are counted under CountMissing.
+//
+// useful for facets and histograms:
+//
+//	counts, err := js.Get("orders").CountValues("status")
+//	// map[string]int{"paid": 12, "pending": 3, "(missing)": 1}
+func (self *Gson) CountValues(key string) (map[string]int, error) {
+	a, err := self.Array()
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for i := range a {
+		m, _ := expandElem(a, i).(map[string]interface{})
+		_, ok := m[key]
+		v := expandMember(m, key)
+		switch {
+		case !ok:
+			counts[CountMissing]++
+		case v == nil:
+			counts["null"]++
+		default:
+			counts[(&Gson{data: v}).AsString()]++
+		}
+	}
+	return counts, nil
+}
+
+// SumField returns the sum of the `key` member of every element of its
+// `array` representation. Each value must be a number or a string holding
+// a JSON number; an element whose value is missing or anything else is an
+// error naming its index, unless `skip` is true, in which case it is left
+// out. The sum of no values is 0.
+//
+//	total, err := js.Get("orders").SumField("amount")
+func (self *Gson) SumField(key string, skip ...bool) (float64, error) {
+	values, err := self.fieldNumbers("SumField", key, skip)
+	if err != nil {
+		return 0, err
+	}
+	sum := 0.0
+	for _, f := range values {
+		sum += f
+	}
+	return sum, nil
+}
+
+// AvgField returns the mean of the `key` member of every element of its
+// `array` representation, collected as for SumField. Having no values to
+// average is an error.
+func (self *Gson) AvgField(key string, skip ...bool) (float64, error) {
+	values, err := self.fieldNumbers("AvgField", key, skip)
+	if err != nil {
+		return 0, err
+	}
+	if len(values) == 0 {
+		return 0, fmt.Errorf("no numeric values for %q", key)
+	}
+	sum := 0.0
+	for _, f := range values {
+		sum += f
+	}
+	return sum / float64(len(values)), nil
+}
+
+// MinField returns the smallest `key` member of the elements of its
+// `array` representation, collected as for SumField. Having no values is
+// an error.
+func (self *Gson) MinField(key string, skip ...bool) (float64, error) {
+	return self.extremeField("MinField", key, skip, func(a, b float64) bool { return a < b })
+}
+
+// MaxField returns the largest `key` member of the elements of its
+// `array` representation, collected as for SumField. Having no values is
+// an error.
+func (self *Gson) MaxField(key string, skip ...bool) (float64, error) {
+	return self.extremeField("MaxField", key, skip, func(a, b float64) bool { return a > b })
+}
+
+func (self *Gson) extremeField(name, key string, skip []bool, better func(a, b float64) bool) (float64, error) {
+	values, err := self.fieldNumbers(name, key, skip)
+	if err != nil {
+		return 0, err
+	}
+	if len(values) == 0 {
+		return 0, fmt.Errorf("no numeric values for %q", key)
+	}
+	best := values[0]
+	for _, f := range values[1:] {
+		if better(f, best) {
+			best = f
+		}
+	}
+	return best, nil
+}
+
+// fieldNumbers returns the numeric `key` members of the elements of its
+// `array` representation for the aggregate `name`
+func (self *Gson) fieldNumbers(name, key string, skip []bool) ([]float64, error) {
+	var skipBad bool
+
+	switch len(skip) {
+	case 0:
+	case 1:
+		skipBad = skip[0]
+	default:
+		log.Panicf("%s() received too many arguments %d", name, len(skip))
+	}
+
+	a, err := self.Array()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]float64, 0, len(a))
+	for i := range a {
+		m, _ := expandElem(a, i).(map[string]interface{})
+		v, ok := m[key]
+		if !ok {
+			if skipBad {
+				continue
+			}
+			return nil, fmt.Errorf("element %d has no %q", i, key)
+		}
+		if s, isString := v.(string); isString && jsonNumber.MatchString(s) {
+			v = json.Number(s)
+		}
+		f, err := (&Gson{data: v}).Float64()
+		if err != nil {
+			if skipBad {
+				continue
+			}
+			return nil, fmt.Errorf("element %d: %q is not a number", i, key)
+		}
+		values = append(values, f)
+	}
+	return values, nil
+}
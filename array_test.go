@@ -0,0 +1,389 @@
+package gson
+
+import (
+	"encoding/json"
+	"git.egret.io/go/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestFlattenArray(t *testing.T) {
+	js, err := NewGson([]byte(`[1, [2, [3, [4]]], "five", {"six": [6]}]`))
+	assert.Equal(t, nil, err)
+
+	one, err := js.FlattenArray(1)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 5, len(one.MustArray()))
+	assert.Equal(t, json.Number("2"), one.GetIndex(1).Interface())
+	b, _ := one.GetIndex(2).Encode()
+	assert.Equal(t, `[3,[4]]`, string(b))
+
+	full, err := js.FlattenArray(-1)
+	assert.Equal(t, nil, err)
+	b, _ = full.Encode()
+	assert.Equal(t, `[1,2,3,4,"five",{"six":[6]}]`, string(b))
+
+	none, err := js.FlattenArray(0)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 4, len(none.MustArray()))
+
+	// the source document is left untouched
+	assert.Equal(t, 4, len(js.MustArray()))
+
+	_, err = New().FlattenArray(-1)
+	assert.NotEqual(t, nil, err)
+
+	lazy, err := NewLazy([]byte(`[1, [2, [3, [4]]]]`))
+	assert.Equal(t, nil, err)
+	full, err = lazy.FlattenArray(-1)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []interface{}{json.Number("1"), json.Number("2"), json.Number("3"), json.Number("4")}, full.Interface())
+}
+
+func TestGetIndexRange(t *testing.T) {
+	js, err := NewGson([]byte(`[{"n":0},{"n":1},{"n":2},{"n":3}]`))
+	assert.Equal(t, nil, err)
+
+	page := js.GetIndexRange(1, 3)
+	assert.Equal(t, 2, len(page))
+	assert.Equal(t, 1, page[0].Get("n").MustInt())
+	assert.Equal(t, 2, page[1].Get("n").MustInt())
+
+	assert.Equal(t, 4, len(js.GetIndexRange(-5, 100)))
+	none := []*Gson{}
+	assert.Equal(t, none, js.GetIndexRange(3, 1))
+	assert.Equal(t, none, js.GetIndexRange(10, 20))
+	assert.Equal(t, none, js.GetIndexRange(-3, 0))
+	assert.Equal(t, none, New().GetIndexRange(0, 1))
+
+	lazy, _ := NewLazy([]byte(`[{"n":1},{"n":2},{"n":3}]`))
+	page = lazy.GetIndexRange(1, 3)
+	assert.Equal(t, 2, page[0].Get("n").MustInt())
+	page[1].Set("seen", true)
+	assert.Equal(t, true, lazy.GetIndex(2).Get("seen").MustBool())
+}
+
+func TestUnionKeys(t *testing.T) {
+	js, err := NewGson([]byte(`[{"b":1,"a":2},{"c":3},"skip me",{"a":null,"d":{}}]`))
+	assert.Equal(t, nil, err)
+
+	keys, err := js.UnionKeys()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, keys)
+
+	_, err = js.UnionKeys(true)
+	assert.NotEqual(t, nil, err)
+
+	_, err = js.GetIndexRange(0, 2)[0].UnionKeys()
+	assert.NotEqual(t, nil, err)
+
+	empty, _ := NewGson([]byte(`[]`))
+	keys, err = empty.UnionKeys(true)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{}, keys)
+
+	lazy, err := NewLazy([]byte(`[{"b":{"x":1}},{"a":[2]}]`))
+	assert.Equal(t, nil, err)
+	keys, err = lazy.UnionKeys(true)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"a", "b"}, keys)
+}
+
+func TestNormalizeKeys(t *testing.T) {
+	js, err := NewGson([]byte(`[{"a":1},{"b":2},7]`))
+	assert.Equal(t, nil, err)
+
+	norm, err := js.NormalizeKeys(nil)
+	assert.Equal(t, nil, err)
+	b, _ := norm.Encode()
+	assert.Equal(t, `[{"a":1,"b":null},{"a":null,"b":2},7]`, string(b))
+
+	// the original is untouched
+	b, _ = js.Encode()
+	assert.Equal(t, `[{"a":1},{"b":2},7]`, string(b))
+
+	norm, err = js.NormalizeKeys(map[string]interface{}{"empty": true})
+	assert.Equal(t, nil, err)
+	norm.GetIndex(0).Get("b").Set("empty", false)
+	assert.Equal(t, true, norm.GetIndex(1).Get("a").Get("empty").MustBool())
+
+	_, err = New().NormalizeKeys(nil)
+	assert.NotEqual(t, nil, err)
+
+	lazy, err := NewLazy([]byte(`[{"a":{"x":1}},{"b":[2]}]`))
+	assert.Equal(t, nil, err)
+	norm, err = lazy.NormalizeKeys(nil)
+	assert.Equal(t, nil, err)
+	b, _ = norm.Encode()
+	assert.Equal(t, `[{"a":{"x":1},"b":null},{"a":null,"b":[2]}]`, string(b))
+}
+
+func TestSample(t *testing.T) {
+	js, err := NewGson([]byte(`[0,1,2,3,4,5,6,7,8,9]`))
+	assert.Equal(t, nil, err)
+
+	s, err := js.Sample(4)
+	assert.Equal(t, nil, err)
+	a := s.MustArray()
+	assert.Equal(t, 4, len(a))
+	last := -1
+	for _, v := range a {
+		n := int(js.child(v).MustInt64())
+		assert.Equal(t, true, n > last)
+		last = n
+	}
+
+	s1, _ := js.SampleWithRand(3, rand.New(rand.NewSource(7)))
+	s2, _ := js.SampleWithRand(3, rand.New(rand.NewSource(7)))
+	assert.Equal(t, s1.MustArray(), s2.MustArray())
+
+	s, err = js.Sample(20)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, js.MustArray(), s.MustArray())
+
+	s, _ = js.Sample(0)
+	assert.Equal(t, []interface{}{}, s.MustArray())
+
+	_, err = js.Sample(-1)
+	assert.NotEqual(t, nil, err)
+
+	_, err = New().Sample(1)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestChunk(t *testing.T) {
+	js, err := NewGson([]byte(`[1,2,3,4,5,6,7]`))
+	assert.Equal(t, nil, err)
+
+	c, err := js.Chunk(3)
+	assert.Equal(t, nil, err)
+	b, _ := c.Encode()
+	assert.Equal(t, `[[1,2,3],[4,5,6],[7]]`, string(b))
+
+	c, _ = js.Chunk(7)
+	b, _ = c.Encode()
+	assert.Equal(t, `[[1,2,3,4,5,6,7]]`, string(b))
+
+	c, _ = js.Chunk(10)
+	assert.Equal(t, 1, len(c.MustArray()))
+
+	empty, _ := NewGson([]byte(`[]`))
+	c, _ = empty.Chunk(2)
+	b, _ = c.Encode()
+	assert.Equal(t, `[]`, string(b))
+
+	_, err = js.Chunk(0)
+	assert.Equal(t, "chunk size must be positive, got 0", err.Error())
+	_, err = js.Chunk(-2)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestReverse(t *testing.T) {
+	js, err := NewGson([]byte(`{"events":[{"id":1},{"id":2},{"id":3}]}`))
+	assert.Equal(t, nil, err)
+
+	r, err := js.Get("events").Reverse()
+	assert.Equal(t, nil, err)
+	b, _ := r.Encode()
+	assert.Equal(t, `[{"id":3},{"id":2},{"id":1}]`, string(b))
+
+	b, _ = js.Get("events").Encode()
+	assert.Equal(t, `[{"id":1},{"id":2},{"id":3}]`, string(b))
+
+	_, err = js.Reverse()
+	assert.NotEqual(t, nil, err)
+}
+
+func TestContains(t *testing.T) {
+	js, err := NewGson([]byte(`[1, 2.5, "three", null, {"id": 4, "tags": ["x"]}, [5]]`))
+	assert.Equal(t, nil, err)
+
+	for _, v := range []interface{}{1, int64(1), 1.0, json.Number("1.0"), 2.5, "three", nil,
+		map[string]interface{}{"id": 4, "tags": []string{"x"}}, []int{5}} {
+		ok, err := js.Contains(v)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, true, ok, v)
+	}
+	for _, v := range []interface{}{2, "1", false, map[string]interface{}{"id": 4}, []int{}} {
+		ok, _ := js.Contains(v)
+		assert.Equal(t, false, ok, v)
+	}
+
+	_, err = New().Contains(1)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestIndexOf(t *testing.T) {
+	js, err := NewGson([]byte(`["a", 2, {"k": "v"}, 2, null]`))
+	assert.Equal(t, nil, err)
+
+	i, err := js.IndexOf(2.0)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, i)
+
+	i, _ = js.IndexOf(map[string]string{"k": "v"})
+	assert.Equal(t, 2, i)
+
+	i, _ = js.IndexOf(nil)
+	assert.Equal(t, 4, i)
+
+	i, _ = js.IndexOf("missing")
+	assert.Equal(t, -1, i)
+
+	i, err = New().IndexOf("a")
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, -1, i)
+
+	lazy, err := NewLazy([]byte(`[{"k": ["v"]}, [1, {"n": 2}]]`))
+	assert.Equal(t, nil, err)
+	i, _ = lazy.IndexOf([]interface{}{1, map[string]interface{}{"n": 2.0}})
+	assert.Equal(t, 1, i)
+}
+
+func TestEnumerate(t *testing.T) {
+	js, err := NewGson([]byte(`{"items":[{"name":"b"},{"name":"a"},"c"]}`))
+	assert.Equal(t, nil, err)
+
+	items, err := js.Get("items").Enumerate()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(items))
+	for i, it := range items {
+		assert.Equal(t, i, it.Index)
+	}
+	assert.Equal(t, "a", items[1].Value.Get("name").MustString())
+	assert.Equal(t, "c", items[2].Value.MustString())
+
+	// the values are live views into the document
+	items[0].Value.Set("seen", true)
+	assert.Equal(t, true, js.Get("items").GetIndex(0).Get("seen").MustBool())
+
+	empty, _ := NewGson([]byte(`[]`))
+	items, err = empty.Enumerate()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(items))
+
+	_, err = js.Enumerate()
+	assert.NotEqual(t, nil, err)
+
+	lazy, _ := NewLazy([]byte(`{"items":[{"name":"b"},["x"]]}`))
+	items, err = lazy.Get("items").Enumerate()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "b", items[0].Value.Get("name").MustString())
+	assert.Equal(t, "x", items[1].Value.GetIndex(0).MustString())
+	items[0].Value.Set("seen", true)
+	assert.Equal(t, true, lazy.Get("items").GetIndex(0).Get("seen").MustBool())
+}
+
+func TestArraySetOperations(t *testing.T) {
+	a, _ := NewGson([]byte(`[1, "x", {"id":1}, 2.0, 1, [3]]`))
+	b, _ := NewGson([]byte(`[2, {"id":1.0}, "y", "y", [4]]`))
+
+	encode := func(js *Gson, err error) string {
+		assert.Equal(t, nil, err)
+		out, _ := js.Encode()
+		return string(out)
+	}
+	assert.Equal(t, `[1,"x",[3]]`, encode(a.ArrayDiff(b)))
+	assert.Equal(t, `["y",[4]]`, encode(b.ArrayDiff(a)))
+	assert.Equal(t, `[{"id":1},2.0]`, encode(a.ArrayIntersect(b)))
+	assert.Equal(t, `[1,"x",{"id":1},2.0,[3],"y",[4]]`, encode(a.ArrayUnion(b)))
+
+	a.MustArray()[1] = []string{"set"}
+	b.MustArray()[2] = []interface{}{"set"}
+	assert.Equal(t, `[["set"],{"id":1},2.0]`, encode(a.ArrayIntersect(b)))
+
+	// the results are independent copies
+	u, _ := a.ArrayUnion(b)
+	u.GetIndex(2).Set("id", 9)
+	assert.Equal(t, 1, a.GetIndex(2).Get("id").MustInt())
+
+	_, err := a.ArrayDiff(New())
+	assert.NotEqual(t, nil, err)
+	_, err = New().ArrayUnion(a)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestCountValues(t *testing.T) {
+	js, err := NewGson([]byte(`[
+		{"status":"paid"},{"status":"paid"},{"status":"pending"},
+		{"status":1},{"status":1.0},{"status":true},{"status":null},{"status":""},
+		{"other":1},"not an object",{"status":{"a":1}}
+	]`))
+	assert.Equal(t, nil, err)
+
+	counts, err := js.CountValues("status")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[string]int{
+		"paid":       2,
+		"pending":    1,
+		"1":          1,
+		"1.0":        1,
+		"true":       1,
+		"null":       1,
+		"":           1,
+		`{"a":1}`:    1,
+		CountMissing: 2,
+	}, counts)
+
+	_, err = New().CountValues("status")
+	assert.NotEqual(t, nil, err)
+
+	lazy, err := NewLazy([]byte(`[{"status":"paid"},{"status":{"a":[1]}},["x"]]`))
+	assert.Equal(t, nil, err)
+	counts, err = lazy.CountValues("status")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[string]int{"paid": 1, `{"a":[1]}`: 1, CountMissing: 1}, counts)
+}
+
+func TestFieldAggregates(t *testing.T) {
+	js, err := NewGson([]byte(`[{"amount":10},{"amount":2.5},{"amount":"7.5"},{"amount":-4}]`))
+	assert.Equal(t, nil, err)
+
+	sum, err := js.SumField("amount")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 16.0, sum)
+	avg, _ := js.AvgField("amount")
+	assert.Equal(t, 4.0, avg)
+	min, _ := js.MinField("amount")
+	assert.Equal(t, -4.0, min)
+	max, _ := js.MaxField("amount")
+	assert.Equal(t, 10.0, max)
+
+	dirty, _ := NewGson([]byte(`[{"amount":1},{"other":2},{"amount":"n/a"},{"amount":null},"x",{"amount":3}]`))
+	_, err = dirty.SumField("amount")
+	assert.Equal(t, `element 1 has no "amount"`, err.Error())
+	_, err = dirty.MaxField("amount")
+	assert.NotEqual(t, nil, err)
+
+	sum, err = dirty.SumField("amount", true)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 4.0, sum)
+	avg, _ = dirty.AvgField("amount", true)
+	assert.Equal(t, 2.0, avg)
+	min, _ = dirty.MinField("amount", true)
+	assert.Equal(t, 1.0, min)
+	max, _ = dirty.MaxField("amount", true)
+	assert.Equal(t, 3.0, max)
+
+	noNumbers, _ := NewGson([]byte(`[{"amount":"x"}]`))
+	_, err = noNumbers.SumField("amount")
+	assert.Equal(t, `element 0: "amount" is not a number`, err.Error())
+	sum, err = noNumbers.SumField("amount", true)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0.0, sum)
+	_, err = noNumbers.AvgField("amount", true)
+	assert.Equal(t, `no numeric values for "amount"`, err.Error())
+	_, err = noNumbers.MinField("amount", true)
+	assert.NotEqual(t, nil, err)
+
+	_, err = New().SumField("amount")
+	assert.NotEqual(t, nil, err)
+
+	lazy, err := NewLazy([]byte(`[{"amount":1,"meta":{}},{"amount":"2"}]`))
+	assert.Equal(t, nil, err)
+	sum, err = lazy.SumField("amount")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3.0, sum)
+	max, _ = lazy.MaxField("amount")
+	assert.Equal(t, 2.0, max)
+}
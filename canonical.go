@@ -0,0 +1,258 @@
+package gson
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+)
+
+// EncodeCanonical returns its data encoded per the JSON Canonicalization
+// Scheme (RFC 8785): no insignificant whitespace, object keys sorted by
+// their UTF-16 code units, numbers formatted as ECMAScript does for IEEE
+// 754 doubles, and strings with only the mandatory escapes.
+//
+// useful for producing reproducible bytes to sign or compare:
+//
+//	payload, err := js.EncodeCanonical()
+func (self *Gson) EncodeCanonical() ([]byte, error) {
+	v, err := jsonValue(self.data)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, v interface{}) error {
+	switch x := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(x))
+	case string:
+		writeCanonicalString(buf, x)
+	case json.Number:
+		f, err := x.Float64()
+		if err != nil {
+			return errors.New("number " + x.String() + " is out of range for canonical encoding")
+		}
+		if f == 0 {
+			// drops the sign of negative zero
+			f = 0
+		}
+		// encoding/json formats float64 the way ECMAScript does
+		b, err := json.Marshal(f)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, e := range x {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, e); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return lessUTF16(keys[i], keys[j])
+		})
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeCanonicalString(buf, k)
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, x[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return errors.New("unsupported value type for canonical encoding")
+	}
+	return nil
+}
+
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	const digits = "0123456789abcdef"
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(digits[r>>4])
+				buf.WriteByte(digits[r&0xf])
+				continue
+			}
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// lessUTF16 orders strings by their UTF-16 code units, as RFC 8785 requires
+func lessUTF16(a, b string) bool {
+	ua := utf16.Encode([]rune(a))
+	ub := utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
+
+// StableHash returns the hex encoded SHA-256 of a canonical encoding of the
+// document, in which object keys are sorted and numbers normalized (so 1,
+// 1.0 and 1e0 are the same). Semantically equal documents hash the same
+// regardless of key order, whitespace or how their numbers were written.
+func (self *Gson) StableHash() (string, error) {
+	v, err := jsonValue(self.data)
+	if err != nil {
+		return "", err
+	}
+	// encoding/json sorts map keys
+	b, err := json.Marshal(copyTree(v, canonicalNumber))
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// HashTree returns a pointer to a new `Gson` object mirroring the
+// structure of the document with hashes in place of values: every scalar
+// becomes the hex encoded SHA-256 of its canonical form, and every object
+// or array becomes `{"hash": ..., "children": ...}`, where `children`
+// holds the hash trees of its members (an object or an array to match) and
+// `hash` is computed from the children's hashes, Merkle style.
+//
+// numbers are normalized as for StableHash. Two hash trees have equal root
+// hashes exactly when the documents are equal, and descending only into
+// children whose hashes differ localizes a change:
+//
+//	if old.Get("hash").MustString() != cur.Get("hash").MustString() {
+//		// compare old.Get("children") against cur.Get("children")
+//	}
+func (self *Gson) HashTree() (*Gson, error) {
+	v, err := jsonValue(self.data)
+	if err != nil {
+		return nil, err
+	}
+	tree, _, err := hashTree(v)
+	if err != nil {
+		return nil, err
+	}
+	return &Gson{data: tree}, nil
+}
+
+// hashTree returns the hash tree of the decoded value `v` and its hash
+func hashTree(v interface{}) (interface{}, string, error) {
+	h := sha256.New()
+	var children interface{}
+	switch c := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(c))
+		h.Write([]byte("object"))
+		for _, k := range sortedKeys(c) {
+			sub, sum, err := hashTree(c[k])
+			if err != nil {
+				return nil, "", err
+			}
+			m[k] = sub
+			key, _ := json.Marshal(k)
+			h.Write(key)
+			h.Write([]byte(sum))
+		}
+		children = m
+	case []interface{}:
+		a := make([]interface{}, len(c))
+		h.Write([]byte("array"))
+		for i, e := range c {
+			sub, sum, err := hashTree(e)
+			if err != nil {
+				return nil, "", err
+			}
+			a[i] = sub
+			h.Write([]byte(sum))
+		}
+		children = a
+	default:
+		b, err := json.Marshal(canonicalNumber(v))
+		if err != nil {
+			return nil, "", err
+		}
+		h.Write([]byte("value"))
+		h.Write(b)
+		sum := hex.EncodeToString(h.Sum(nil))
+		return sum, sum, nil
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	return map[string]interface{}{"hash": sum, "children": children}, sum, nil
+}
+
+// EncodeGolden returns a deterministic, human-readable encoding meant for
+// snapshot test fixtures: object keys sorted, numbers normalized as for
+// StableHash (so 1.0 and 1 both print as 1, and large integers keep their
+// digits), two-space indentation and a trailing newline.
+func (self *Gson) EncodeGolden() ([]byte, error) {
+	v, err := jsonValue(self.data)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.MarshalIndent(copyTree(v, canonicalNumber), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// canonicalNumber rewrites a numeric leaf into a single representation:
+// exact digits for integral values, the shortest float64 form otherwise
+func canonicalNumber(v interface{}) interface{} {
+	r, ok := numberRat(v)
+	if !ok {
+		return v
+	}
+	if r.IsInt() {
+		return json.Number(r.Num().String())
+	}
+	f, _ := r.Float64()
+	if n, ok := toNumber(f); ok {
+		return n
+	}
+	return v
+}
@@ -0,0 +1,135 @@
+package gson
+
+import (
+	"git.egret.io/go/assert"
+	"testing"
+)
+
+func TestStableHash(t *testing.T) {
+	a, _ := NewGson([]byte(`{"b":[1,2.50,{"y":"z","x":1e2}],"a":18446744073709551616}`))
+	b, _ := NewGson([]byte(`{
+		"a": 18446744073709551616,
+		"b": [1.0, 2.5, {"x": 100, "y": "z"}]
+	}`))
+	c, _ := NewGson([]byte(`{"a":18446744073709551617,"b":[1,2.5,{"x":100,"y":"z"}]}`))
+
+	ha, err := a.StableHash()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 64, len(ha))
+	hb, err := b.StableHash()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, ha, hb)
+	hc, err := c.StableHash()
+	assert.Equal(t, nil, err)
+	assert.NotEqual(t, ha, hc)
+
+	// programmatically built documents hash like parsed ones
+	d := New()
+	d.Set("a", 1)
+	d.Set("b", []string{"x"})
+	e, _ := NewGson([]byte(`{"b":["x"],"a":1.0}`))
+	hd, _ := d.StableHash()
+	he, _ := e.StableHash()
+	assert.Equal(t, hd, he)
+}
+
+func TestEncodeCanonical(t *testing.T) {
+	// the example from RFC 8785 section 3.2.2
+	js, err := NewGson([]byte(`{
+		"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+		"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+		"literals": [null, true, false]
+	}`))
+	assert.Equal(t, nil, err)
+
+	b, err := js.EncodeCanonical()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],`+
+		`"string":"€$\u000f\nA'B\"\\\\\"/"}`, string(b))
+
+	// sorting by UTF-16 code units, from RFC 8785 section 3.2.3
+	js, err = NewGson([]byte(`{"\u20ac":1,"\r":2,"\ufb33":3,"1":4,"\ud83d\ude00":5,"\u0080":6,"\u00f6":7,"<&>":8}`))
+	assert.Equal(t, nil, err)
+	b, err = js.EncodeCanonical()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "{\"\\r\":2,\"1\":4,\"<&>\":8,\"\u0080\":6,\"ö\":7,\"€\":1,\"😀\":5,\"\ufb33\":3}", string(b))
+
+	js, _ = NewGson([]byte(`[-0, 100, 1e21, -1.5e-7]`))
+	b, err = js.EncodeCanonical()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `[0,100,1e+21,-1.5e-7]`, string(b))
+
+	js, _ = NewGson([]byte(`[1e400]`))
+	_, err = js.EncodeCanonical()
+	assert.NotEqual(t, nil, err)
+}
+
+func TestEncodeGolden(t *testing.T) {
+	js, err := NewGson([]byte(`{"z":1.0,"a":[2.50,{"y":true,"x":null}],"id":18446744073709551615,"m":{}}`))
+	assert.Equal(t, nil, err)
+
+	b, err := js.EncodeGolden()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{
+  "a": [
+    2.5,
+    {
+      "x": null,
+      "y": true
+    }
+  ],
+  "id": 18446744073709551615,
+  "m": {},
+  "z": 1
+}
+`, string(b))
+
+	// stable whatever the source formatting
+	other, _ := NewGson([]byte(`{"id":18446744073709551615,"m":{},"a":[2.5,{"x":null,"y":true}],"z":1}`))
+	b2, _ := other.EncodeGolden()
+	assert.Equal(t, string(b), string(b2))
+}
+
+func TestHashTree(t *testing.T) {
+	a, _ := NewGson([]byte(`{"users":[{"id":1,"name":"ann"},{"id":2,"name":"bob"}],"meta":{"v":1.0}}`))
+	b, _ := NewGson([]byte(`{"meta":{"v":1},"users":[{"id":1,"name":"ann"},{"id":2,"name":"bobby"}]}`))
+
+	ha, err := a.HashTree()
+	assert.Equal(t, nil, err)
+	hb, err := b.HashTree()
+	assert.Equal(t, nil, err)
+
+	hash := func(js *Gson, branch ...string) string {
+		return js.GetPath(branch...).Get("hash").MustString()
+	}
+	assert.Equal(t, 64, len(hash(ha)))
+	assert.NotEqual(t, hash(ha), hash(hb))
+	assert.Equal(t, hash(ha, "children", "meta"), hash(hb, "children", "meta"))
+	assert.NotEqual(t, hash(ha, "children", "users"), hash(hb, "children", "users"))
+
+	ua := ha.GetPath("children", "users", "children")
+	ub := hb.GetPath("children", "users", "children")
+	assert.Equal(t, ua.GetIndex(0).Get("hash").MustString(), ub.GetIndex(0).Get("hash").MustString())
+	assert.Equal(t,
+		ua.GetIndex(1).GetPath("children", "id").MustString(),
+		ub.GetIndex(1).GetPath("children", "id").MustString())
+	assert.NotEqual(t,
+		ua.GetIndex(1).GetPath("children", "name").MustString(),
+		ub.GetIndex(1).GetPath("children", "name").MustString())
+
+	// equal documents hash the same, however they were written
+	c, _ := NewGson([]byte(`{ "users" : [{"name":"ann","id":1e0},{"name":"bob","id":2}], "meta":{"v":1}}`))
+	hc, _ := c.HashTree()
+	assert.Equal(t, hash(ha), hash(hc))
+
+	// containers and scalars with the same text don't collide
+	x, _ := NewGson([]byte(`[[]]`))
+	y, _ := NewGson([]byte(`[{}]`))
+	hx, _ := x.HashTree()
+	hy, _ := y.HashTree()
+	assert.NotEqual(t, hash(hx), hash(hy))
+
+	s, _ := NewGson([]byte(`"x"`))
+	hs, _ := s.HashTree()
+	assert.Equal(t, 64, len(hs.MustString()))
+}
@@ -0,0 +1,252 @@
+package gson
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// EqualValue reports whether the node is equal to the plain Go value `v`
+// once both are reduced to their JSON form. Numbers compare by value, so
+// a parsed json.Number("42") equals int 42 and float64 42.0:
+//
+//	js.Get("x").EqualValue(42)
+func (self *Gson) EqualValue(v interface{}) bool {
+	a, err := jsonValue(self.data)
+	if err != nil {
+		return false
+	}
+	b, err := jsonValue(v)
+	if err != nil {
+		return false
+	}
+	return valuesEqual(a, b)
+}
+
+// EqualIgnoring is like EqualValue against another document but skips the
+// nodes at `ignorePaths`, given as JSON Pointers, on both sides: an ignored
+// node may differ, or be present on only one side. A `*` segment matches
+// any single key or array index, so "/items/*/updatedAt" ignores that
+// member of every element. Malformed pointers are skipped.
+//
+// useful for golden-response tests with volatile fields:
+//
+//	js.EqualIgnoring(golden, []string{"/requestId", "/items/*/updatedAt"})
+func (self *Gson) EqualIgnoring(other *Gson, ignorePaths []string) bool {
+	a, err := jsonValue(self.data)
+	if err != nil {
+		return false
+	}
+	b, err := jsonValue(other.data)
+	if err != nil {
+		return false
+	}
+	var patterns [][]string
+	for _, p := range ignorePaths {
+		if tokens, err := parsePointer(p); err == nil {
+			patterns = append(patterns, tokens)
+		}
+	}
+	return equalIgnoring(a, b, patterns)
+}
+
+// equalIgnoring compares `a` and `b` except below the `patterns`, which
+// are the remainders of the ignored paths relative to the current node
+func equalIgnoring(a, b interface{}, patterns [][]string) bool {
+	if len(patterns) == 0 {
+		return valuesEqual(a, b)
+	}
+	for _, p := range patterns {
+		if len(p) == 0 {
+			return true
+		}
+	}
+	below := func(seg string) [][]string {
+		var rest [][]string
+		for _, p := range patterns {
+			if p[0] == seg || p[0] == "*" {
+				rest = append(rest, p[1:])
+			}
+		}
+		return rest
+	}
+	ignored := func(seg string) bool {
+		for _, p := range below(seg) {
+			if len(p) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, xv := range x {
+			yv, ok := y[k]
+			if !ok {
+				if !ignored(k) {
+					return false
+				}
+				continue
+			}
+			if !equalIgnoring(xv, yv, below(k)) {
+				return false
+			}
+		}
+		for k := range y {
+			if _, ok := x[k]; !ok && !ignored(k) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok {
+			return false
+		}
+		for i := 0; i < len(x) || i < len(y); i++ {
+			seg := strconv.Itoa(i)
+			if i >= len(x) || i >= len(y) {
+				if !ignored(seg) {
+					return false
+				}
+				continue
+			}
+			if !equalIgnoring(x[i], y[i], below(seg)) {
+				return false
+			}
+		}
+		return true
+	}
+	return valuesEqual(a, b)
+}
+
+// SameShape reports whether both documents have the same structure
+// regardless of scalar contents: objects with the same keys, arrays of the
+// same length, and matching kinds (string, number, bool, null) at every
+// position. It is useful for checking a response against an example.
+func (self *Gson) SameShape(other *Gson) bool {
+	a, err := jsonValue(self.data)
+	if err != nil {
+		return false
+	}
+	b, err := jsonValue(other.data)
+	if err != nil {
+		return false
+	}
+	return sameShape(a, b)
+}
+
+func sameShape(a, b interface{}) bool {
+	if kindOf(a) != kindOf(b) {
+		return false
+	}
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y := b.(map[string]interface{})
+		if len(x) != len(y) {
+			return false
+		}
+		for k, xv := range x {
+			yv, ok := y[k]
+			if !ok || !sameShape(xv, yv) {
+				return false
+			}
+		}
+	case []interface{}:
+		y := b.([]interface{})
+		if len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !sameShape(x[i], y[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// kindOf names the JSON kind of a decoded value, as Peek does
+func kindOf(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case nil:
+		return "null"
+	}
+	if _, ok := toNumber(v); ok {
+		return "number"
+	}
+	return "unknown"
+}
+
+// jsonValue returns `v` as it would be decoded after a round trip through
+// its JSON encoding
+func jsonValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	err = decodeBytes(b, &out)
+	return out, err
+}
+
+// valuesEqual deep compares two decoded values, treating numbers of any
+// representation as equal when they denote the same value. Subtrees left
+// raw by NewLazy are decoded for the comparison.
+func valuesEqual(a, b interface{}) bool {
+	a, b = expandLazy(a), expandLazy(b)
+	if ra, ok := numberRat(a); ok {
+		rb, ok := numberRat(b)
+		return ok && ra.Cmp(rb) == 0
+	}
+
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, xv := range x {
+			yv, ok := y[k]
+			if !ok || !valuesEqual(xv, yv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !valuesEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// numberRat returns the exact value of a numeric leaf
+func numberRat(v interface{}) (*big.Rat, bool) {
+	n, ok := toNumber(v)
+	if !ok {
+		return nil, false
+	}
+	return new(big.Rat).SetString(n.String())
+}
@@ -0,0 +1,89 @@
+package gson
+
+import (
+	"git.egret.io/go/assert"
+	"testing"
+)
+
+func TestEqualValue(t *testing.T) {
+	js, err := NewGson([]byte(`{"x":42,"f":1.50,"big":18446744073709551615,"s":"str","n":null,
+		"arr":[1,"two",{"three":3}],"obj":{"a":true}}`))
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, true, js.Get("x").EqualValue(42))
+	assert.Equal(t, true, js.Get("x").EqualValue(int8(42)))
+	assert.Equal(t, true, js.Get("x").EqualValue(42.0))
+	assert.Equal(t, false, js.Get("x").EqualValue(43))
+	assert.Equal(t, false, js.Get("x").EqualValue("42"))
+	assert.Equal(t, true, js.Get("f").EqualValue(1.5))
+	assert.Equal(t, true, js.Get("big").EqualValue(uint64(18446744073709551615)))
+	assert.Equal(t, false, js.Get("big").EqualValue(uint64(18446744073709551614)))
+	assert.Equal(t, true, js.Get("s").EqualValue("str"))
+	assert.Equal(t, true, js.Get("n").EqualValue(nil))
+	assert.Equal(t, true, js.Get("missing").EqualValue(nil))
+	assert.Equal(t, true, js.Get("arr").EqualValue([]interface{}{1, "two", map[string]int{"three": 3}}))
+	assert.Equal(t, false, js.Get("arr").EqualValue([]interface{}{1, "two"}))
+	assert.Equal(t, true, js.Get("obj").EqualValue(struct {
+		A bool `json:"a"`
+	}{true}))
+	assert.Equal(t, false, js.Get("obj").EqualValue(map[string]interface{}{"a": true, "b": 1}))
+	assert.Equal(t, false, js.Get("obj").EqualValue(func() {}))
+
+	js.Set("set", []string{"a", "b"})
+	assert.Equal(t, true, js.Get("set").EqualValue([]interface{}{"a", "b"}))
+}
+
+func TestSameShape(t *testing.T) {
+	expected, _ := NewGson([]byte(`{"id":1,"name":"x","tags":["a","b"],"meta":{"ok":true,"next":null}}`))
+
+	same, _ := NewGson([]byte(`{"name":"other","id":99.5,"tags":["c","d"],"meta":{"next":null,"ok":false}}`))
+	assert.Equal(t, true, expected.SameShape(same))
+	assert.Equal(t, true, same.SameShape(expected))
+
+	for _, body := range []string{
+		`{"id":"1","name":"x","tags":["a","b"],"meta":{"ok":true,"next":null}}`,
+		`{"id":1,"name":"x","tags":["a"],"meta":{"ok":true,"next":null}}`,
+		`{"id":1,"name":"x","tags":["a",2],"meta":{"ok":true,"next":null}}`,
+		`{"id":1,"name":"x","tags":["a","b"],"meta":{"ok":true}}`,
+		`{"id":1,"name":"x","tags":["a","b"],"meta":{"ok":true,"next":{}}}`,
+		`{"id":1,"name":"x","tags":["a","b"],"meta":{"ok":true,"next":null},"extra":1}`,
+		`[]`,
+	} {
+		other, _ := NewGson([]byte(body))
+		assert.Equal(t, false, expected.SameShape(other), body)
+	}
+
+	built := New()
+	built.Set("n", 3)
+	built.Set("list", []string{"x"})
+	parsed, _ := NewGson([]byte(`{"n":0.5,"list":["y"]}`))
+	assert.Equal(t, true, built.SameShape(parsed))
+}
+
+func TestEqualIgnoring(t *testing.T) {
+	a, err := NewGson([]byte(`{"requestId":"r1","items":[{"id":1,"updatedAt":"t1"},{"id":2,"updatedAt":"t2"}],"total":2,"meta":{"took":5}}`))
+	assert.Equal(t, nil, err)
+	b, err := NewGson([]byte(`{"requestId":"r2","items":[{"id":1,"updatedAt":"t9"},{"id":2.0,"updatedAt":"t8"}],"total":2,"meta":{"took":9}}`))
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, false, a.EqualIgnoring(b, nil))
+	assert.Equal(t, false, a.EqualIgnoring(b, []string{"/requestId", "/meta/took"}))
+	assert.Equal(t, true, a.EqualIgnoring(b, []string{"/requestId", "/meta/took", "/items/*/updatedAt"}))
+	assert.Equal(t, true, a.EqualIgnoring(b, []string{"/requestId", "/meta", "/items/*/updatedAt"}))
+	assert.Equal(t, false, a.EqualIgnoring(b, []string{"/requestId", "/meta", "/items/0/updatedAt"}))
+
+	// an ignored member may be missing on one side
+	b.Del("requestId")
+	assert.Equal(t, true, a.EqualIgnoring(b, []string{"/requestId", "/meta", "/items/*/updatedAt"}))
+	b.Set("extra", 1)
+	assert.Equal(t, false, a.EqualIgnoring(b, []string{"/requestId", "/meta", "/items/*/updatedAt"}))
+
+	// genuine differences are still caught
+	c, _ := NewGson([]byte(`{"requestId":"r3","items":[{"id":1,"updatedAt":"x"},{"id":3,"updatedAt":"y"}],"total":2,"meta":{}}`))
+	assert.Equal(t, false, a.EqualIgnoring(c, []string{"/requestId", "/meta", "/items/*/updatedAt"}))
+
+	x, _ := NewGson([]byte(`{"a/b":1,"~":2,"k":3}`))
+	y, _ := NewGson([]byte(`{"a/b":5,"~":6,"k":3}`))
+	assert.Equal(t, true, x.EqualIgnoring(y, []string{"/a~1b", "/~0", "bad pointer"}))
+	assert.Equal(t, true, x.EqualIgnoring(y, []string{""}))
+}
@@ -0,0 +1,405 @@
+package gson
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DecodeInto stores its data in the value pointed to by `v`, following the
+// rules of json.Unmarshal (struct fields matched by `json` tag or by name,
+// case-insensitively; unknown keys ignored; null leaving non-nillable
+// values alone), but assigning the tree directly with reflection instead
+// of encoding it to JSON and parsing it back. Numbers are range checked
+// against the target type, values implementing json.Unmarshaler or
+// encoding.TextUnmarshaler are handed their own encoding, and `interface{}`
+// targets receive a copy of the subtree as the document holds it (numbers
+// stay `json.Number`).
+//
+// errors name the offending value as a JSON Pointer:
+//
+//	var cfg Config
+//	err := js.Get("config").DecodeInto(&cfg)
+func (self *Gson) DecodeInto(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("DecodeInto requires a non-nil pointer")
+	}
+	return decodeReflect(nil, self.data, rv.Elem())
+}
+
+// AsType is like DecodeInto for a type known only at run time: it returns
+// its data converted to a new value of type `t`, with the same rules and
+// errors
+//
+//	v, err := js.Get("options").AsType(plugin.OptionsType())
+func (self *Gson) AsType(t reflect.Type) (interface{}, error) {
+	if t == nil {
+		return nil, errors.New("AsType requires a type")
+	}
+	v := reflect.New(t).Elem()
+	if err := decodeReflect(nil, self.data, v); err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonNumberType      = reflect.TypeOf(json.Number(""))
+)
+
+// decodeReflect assigns the decoded value `src`, found at `path`, to the
+// addressable `dst`
+func decodeReflect(path []string, src interface{}, dst reflect.Value) error {
+	switch s := src.(type) {
+	case nil, map[string]interface{}, []interface{}, string, bool, json.Number:
+	case json.RawMessage:
+		// left undecoded by NewLazy, decode one level and carry on so that
+		// numbers and errors come out as for a parsed document
+		src = parseShallow(s)
+	default:
+		if _, ok := toNumber(src); !ok {
+			// a Go value added with Set, reduce it to its JSON form
+			v, err := jsonValue(src)
+			if err != nil {
+				return decodeError(path, "%v", err)
+			}
+			src = v
+		}
+	}
+
+	if dst.Kind() != reflect.Ptr && reflect.PtrTo(dst.Type()).Implements(jsonUnmarshalerType) {
+		b, err := json.Marshal(src)
+		if err != nil {
+			return decodeError(path, "%v", err)
+		}
+		if err := dst.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(b); err != nil {
+			return decodeError(path, "%v", err)
+		}
+		return nil
+	}
+
+	if src == nil {
+		switch dst.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+			dst.Set(reflect.Zero(dst.Type()))
+		}
+		return nil
+	}
+
+	if s, ok := src.(string); ok && dst.Kind() != reflect.Ptr && reflect.PtrTo(dst.Type()).Implements(textUnmarshalerType) {
+		if err := dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return decodeError(path, "%v", err)
+		}
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return decodeReflect(path, src, dst.Elem())
+
+	case reflect.Interface:
+		if dst.NumMethod() != 0 {
+			break
+		}
+		dst.Set(reflect.ValueOf(deepCopy(src)))
+		return nil
+
+	case reflect.String:
+		if dst.Type() == jsonNumberType {
+			if n, ok := toNumber(src); ok {
+				dst.SetString(n.String())
+				return nil
+			}
+			break
+		}
+		if s, ok := src.(string); ok {
+			dst.SetString(s)
+			return nil
+		}
+
+	case reflect.Bool:
+		if b, ok := src.(bool); ok {
+			dst.SetBool(b)
+			return nil
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := toNumber(src)
+		if !ok {
+			break
+		}
+		i, err := strconv.ParseInt(n.String(), 10, 64)
+		if err != nil || dst.OverflowInt(i) {
+			return decodeError(path, "number %s does not fit %s", n, dst.Type())
+		}
+		dst.SetInt(i)
+		return nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, ok := toNumber(src)
+		if !ok {
+			break
+		}
+		u, err := strconv.ParseUint(n.String(), 10, 64)
+		if err != nil || dst.OverflowUint(u) {
+			return decodeError(path, "number %s does not fit %s", n, dst.Type())
+		}
+		dst.SetUint(u)
+		return nil
+
+	case reflect.Float32, reflect.Float64:
+		n, ok := toNumber(src)
+		if !ok {
+			break
+		}
+		f, err := strconv.ParseFloat(n.String(), dst.Type().Bits())
+		if err != nil || dst.OverflowFloat(f) {
+			return decodeError(path, "number %s does not fit %s", n, dst.Type())
+		}
+		dst.SetFloat(f)
+		return nil
+
+	case reflect.Slice:
+		if s, ok := src.(string); ok && dst.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return decodeError(path, "%v", err)
+			}
+			dst.SetBytes(b)
+			return nil
+		}
+		a, ok := src.([]interface{})
+		if !ok {
+			break
+		}
+		out := reflect.MakeSlice(dst.Type(), len(a), len(a))
+		for i, e := range a {
+			if err := decodeReflect(append(path, strconv.Itoa(i)), e, out.Index(i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(out)
+		return nil
+
+	case reflect.Array:
+		a, ok := src.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < dst.Len(); i++ {
+			if i >= len(a) {
+				dst.Index(i).Set(reflect.Zero(dst.Type().Elem()))
+				continue
+			}
+			if err := decodeReflect(append(path, strconv.Itoa(i)), a[i], dst.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		m, ok := src.(map[string]interface{})
+		if !ok {
+			break
+		}
+		t := dst.Type()
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(t, len(m)))
+		}
+		for k, e := range m {
+			key := reflect.New(t.Key()).Elem()
+			if err := decodeMapKey(k, key); err != nil {
+				return decodeError(append(path, k), "%v", err)
+			}
+			elem := reflect.New(t.Elem()).Elem()
+			if err := decodeReflect(append(path, k), e, elem); err != nil {
+				return err
+			}
+			dst.SetMapIndex(key, elem)
+		}
+		return nil
+
+	case reflect.Struct:
+		m, ok := src.(map[string]interface{})
+		if !ok {
+			break
+		}
+		fields := structFields(dst.Type())
+		for k, e := range m {
+			f := fields.lookup(k)
+			if f == nil {
+				continue
+			}
+			if f.quoted {
+				if s, ok := e.(string); ok {
+					if err := decodeBytes([]byte(s), &e); err != nil {
+						return decodeError(append(path, k), "invalid quoted value %q", s)
+					}
+				}
+			}
+			if err := decodeReflect(append(path, k), e, fieldByIndex(dst, f.index)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return decodeError(path, "cannot decode %s into %s", kindOf(src), dst.Type())
+}
+
+func decodeError(path []string, format string, args ...interface{}) error {
+	if len(path) == 0 {
+		return fmt.Errorf(format, args...)
+	}
+	return fmt.Errorf("%s: %s", formatPointer(path), fmt.Sprintf(format, args...))
+}
+
+// decodeMapKey converts the object key `k` for a map keyed by `key`'s type
+func decodeMapKey(k string, key reflect.Value) error {
+	if reflect.PtrTo(key.Type()).Implements(textUnmarshalerType) {
+		return key.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(k))
+	}
+	switch key.Kind() {
+	case reflect.String:
+		key.SetString(k)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(k, 10, 64)
+		if err != nil || key.OverflowInt(i) {
+			return fmt.Errorf("key %q does not fit %s", k, key.Type())
+		}
+		key.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(k, 10, 64)
+		if err != nil || key.OverflowUint(u) {
+			return fmt.Errorf("key %q does not fit %s", k, key.Type())
+		}
+		key.SetUint(u)
+		return nil
+	}
+	return fmt.Errorf("unsupported map key type %s", key.Type())
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but allocates nil
+// embedded struct pointers on the way
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+type decodeField struct {
+	name   string
+	index  []int
+	quoted bool
+}
+
+type decodeFields struct {
+	byName map[string]*decodeField
+	list   []*decodeField
+}
+
+// lookup finds the field for the object key `k`, preferring an exact match
+// over a case-insensitive one
+func (fs *decodeFields) lookup(k string) *decodeField {
+	if f, ok := fs.byName[k]; ok {
+		return f
+	}
+	for _, f := range fs.list {
+		if strings.EqualFold(f.name, k) {
+			return f
+		}
+	}
+	return nil
+}
+
+var fieldCache sync.Map // map[reflect.Type]*decodeFields
+
+// structFields returns the decodable fields of the struct type `t`. Fields
+// of embedded structs are promoted, shallower fields winning on conflict.
+func structFields(t reflect.Type) *decodeFields {
+	if fs, ok := fieldCache.Load(t); ok {
+		return fs.(*decodeFields)
+	}
+	fs := &decodeFields{byName: map[string]*decodeField{}}
+	collectFields(fs, t, nil, map[reflect.Type]bool{})
+	actual, _ := fieldCache.LoadOrStore(t, fs)
+	return actual.(*decodeFields)
+}
+
+func collectFields(fs *decodeFields, t reflect.Type, prefix []int, seen map[reflect.Type]bool) {
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+
+	var embedded []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, sf)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if _, taken := fs.byName[name]; taken {
+			continue
+		}
+		f := &decodeField{
+			name:   name,
+			index:  append(append([]int{}, prefix...), i),
+			quoted: strings.Contains(","+opts+",", ",string,"),
+		}
+		fs.byName[name] = f
+		fs.list = append(fs.list, f)
+	}
+
+	// promoted fields come after the struct's own
+	for _, sf := range embedded {
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			if !sf.IsExported() {
+				// can't allocate through an unexported pointer
+				continue
+			}
+			ft = ft.Elem()
+		}
+		collectFields(fs, ft, append(append([]int{}, prefix...), sf.Index...), seen)
+	}
+}
@@ -0,0 +1,178 @@
+package gson
+
+import (
+	"encoding/json"
+	"git.egret.io/go/assert"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type decodeBase struct {
+	ID      int64 `json:"id"`
+	Created time.Time
+}
+
+type decodeUser struct {
+	decodeBase
+	Name    string            `json:"name"`
+	Email   *string           `json:"email"`
+	Age     uint8             `json:"age"`
+	Score   float32           `json:"score"`
+	Admin   bool              `json:"admin"`
+	Tags    []string          `json:"tags"`
+	Pair    [2]int            `json:"pair"`
+	Limits  map[string]int    `json:"limits"`
+	ByID    map[int]string    `json:"by_id"`
+	Extra   interface{}       `json:"extra"`
+	Raw     []byte            `json:"raw"`
+	Big     json.Number       `json:"big"`
+	Count   int               `json:"count,string"`
+	Skipped string            `json:"-"`
+	Nested  *decodeBase       `json:"nested"`
+	Meta    map[string]string `json:"meta"`
+	private int
+}
+
+func TestDecodeInto(t *testing.T) {
+	js, err := NewGson([]byte(`{
+		"id": 7, "created": "2024-01-02T03:04:05Z",
+		"NAME": "ann", "email": "ann@example.com", "age": 31, "score": 1.5, "admin": true,
+		"tags": ["a", "b"], "pair": [1, 2, 3], "limits": {"cpu": 2}, "by_id": {"10": "x"},
+		"extra": {"k": [1, null]}, "raw": "aGk=", "big": 18446744073709551616,
+		"count": "12", "Skipped": "no", "nested": {"id": 9}, "meta": null, "unknown": 1
+	}`))
+	assert.Equal(t, nil, err)
+
+	u := decodeUser{Meta: map[string]string{"keep": "me"}, Skipped: "kept"}
+	assert.Equal(t, nil, js.DecodeInto(&u))
+	assert.Equal(t, int64(7), u.ID)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), u.Created)
+	assert.Equal(t, "ann", u.Name)
+	assert.Equal(t, "ann@example.com", *u.Email)
+	assert.Equal(t, uint8(31), u.Age)
+	assert.Equal(t, float32(1.5), u.Score)
+	assert.Equal(t, true, u.Admin)
+	assert.Equal(t, []string{"a", "b"}, u.Tags)
+	assert.Equal(t, [2]int{1, 2}, u.Pair)
+	assert.Equal(t, map[string]int{"cpu": 2}, u.Limits)
+	assert.Equal(t, map[int]string{10: "x"}, u.ByID)
+	assert.Equal(t, map[string]interface{}{"k": []interface{}{json.Number("1"), nil}}, u.Extra)
+	assert.Equal(t, []byte("hi"), u.Raw)
+	assert.Equal(t, json.Number("18446744073709551616"), u.Big)
+	assert.Equal(t, 12, u.Count)
+	assert.Equal(t, "kept", u.Skipped)
+	assert.Equal(t, int64(9), u.Nested.ID)
+	assert.Equal(t, map[string]string(nil), u.Meta)
+
+	// the result matches a JSON round trip, except that interface values
+	// keep their json.Number numbers rather than becoming float64
+	var viaJSON decodeUser
+	assert.Equal(t, nil, decodeValue(js.Interface(), &viaJSON))
+	viaJSON.Skipped = "kept"
+	viaJSON.Extra = u.Extra
+	assert.Equal(t, viaJSON, u)
+
+	// decoded interface values don't alias the document
+	u.Extra.(map[string]interface{})["k"] = "changed"
+	assert.Equal(t, 2, len(js.GetPath("extra", "k").MustArray()))
+}
+
+func TestDecodeIntoErrors(t *testing.T) {
+	js, _ := NewGson([]byte(`{"age":300,"tags":["a",2],"name":5}`))
+
+	var u decodeUser
+	assert.Equal(t, "/age: number 300 does not fit uint8", js.Get("age").Wrap("age").DecodeInto(&u).Error())
+	assert.Equal(t, "/tags/1: cannot decode number into string", js.Get("tags").Wrap("tags").DecodeInto(&u).Error())
+	assert.Equal(t, "/name: cannot decode number into string", js.Get("name").Wrap("name").DecodeInto(&u).Error())
+
+	var n int
+	assert.Equal(t, "cannot decode object into int", js.DecodeInto(&n).Error())
+	f, _ := NewGson([]byte(`1.5`))
+	assert.NotEqual(t, nil, f.DecodeInto(&n))
+
+	assert.NotEqual(t, nil, js.DecodeInto(u))
+	assert.NotEqual(t, nil, js.DecodeInto(nil))
+}
+
+func TestDecodeIntoSetAndLazy(t *testing.T) {
+	js := New()
+	js.Set("tags", []string{"x", "y"})
+	js.Set("age", 40)
+	js.Set("nested", map[string]interface{}{"id": int64(3)})
+
+	var u decodeUser
+	assert.Equal(t, nil, js.DecodeInto(&u))
+	assert.Equal(t, []string{"x", "y"}, u.Tags)
+	assert.Equal(t, uint8(40), u.Age)
+	assert.Equal(t, int64(3), u.Nested.ID)
+
+	lazy, err := NewLazy([]byte(`{"name":"lazy","tags":["p"],"nested":{"id":5}}`))
+	assert.Equal(t, nil, err)
+	u = decodeUser{}
+	assert.Equal(t, nil, lazy.DecodeInto(&u))
+	assert.Equal(t, "lazy", u.Name)
+	assert.Equal(t, []string{"p"}, u.Tags)
+	assert.Equal(t, int64(5), u.Nested.ID)
+
+	var v interface{}
+	assert.Equal(t, nil, lazy.DecodeInto(&v))
+	assert.Equal(t, map[string]interface{}{
+		"name":   "lazy",
+		"tags":   []interface{}{"p"},
+		"nested": map[string]interface{}{"id": json.Number("5")},
+	}, v)
+
+	lazy, _ = NewLazy([]byte(`{"nested":{"id":"five"}}`))
+	assert.Equal(t, "/nested/id: cannot decode string into int64", lazy.DecodeInto(&u).Error())
+}
+
+func TestAsType(t *testing.T) {
+	js, err := NewGson([]byte(`{"id":7,"name":"ann","tags":["a"],"ratio":0.5}`))
+	assert.Equal(t, nil, err)
+
+	v, err := js.AsType(reflect.TypeOf(decodeUser{}))
+	assert.Equal(t, nil, err)
+	u := v.(decodeUser)
+	assert.Equal(t, int64(7), u.ID)
+	assert.Equal(t, "ann", u.Name)
+	assert.Equal(t, []string{"a"}, u.Tags)
+
+	v, err = js.Get("id").AsType(reflect.TypeOf(int16(0)))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int16(7), v)
+
+	v, _ = js.Get("tags").AsType(reflect.TypeOf([]string{}))
+	assert.Equal(t, []string{"a"}, v)
+
+	v, _ = js.AsType(reflect.TypeOf(map[string]interface{}{}))
+	assert.Equal(t, "ann", v.(map[string]interface{})["name"])
+
+	v, _ = js.Get("ratio").AsType(reflect.TypeOf((*float64)(nil)))
+	assert.Equal(t, 0.5, *v.(*float64))
+
+	_, err = js.Get("ratio").AsType(reflect.TypeOf(0))
+	assert.Equal(t, "number 0.5 does not fit int", err.Error())
+	_, err = js.Get("name").AsType(reflect.TypeOf(true))
+	assert.Equal(t, "cannot decode string into bool", err.Error())
+	_, err = js.AsType(nil)
+	assert.NotEqual(t, nil, err)
+}
+
+func BenchmarkDecodeInto(b *testing.B) {
+	js, _ := NewGson([]byte(`{"id":7,"name":"ann","age":31,"tags":["a","b","c"],"limits":{"cpu":2,"mem":4},"nested":{"id":9}}`))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var u decodeUser
+		js.DecodeInto(&u)
+	}
+}
+
+func BenchmarkDecodeIntoRoundTrip(b *testing.B) {
+	js, _ := NewGson([]byte(`{"id":7,"name":"ann","age":31,"tags":["a","b","c"],"limits":{"cpu":2,"mem":4},"nested":{"id":9}}`))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var u decodeUser
+		decodeValue(js.Interface(), &u)
+	}
+}
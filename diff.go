@@ -0,0 +1,97 @@
+package gson
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// DiffText describes how `other` differs from the document as a
+// line-oriented report meant for people rather than programs. Each line
+// names a JSON Pointer and starts with `~` for a changed value, `+` for one
+// only in `other` and `-` for one only in the document:
+//
+//	~ /server/port: 80 => 8080
+//	+ /server/host: "example.com"
+//	- /debug
+//
+// objects and arrays are compared member by member, so only the leaves
+// that differ are listed, in key order; a value whose kind changes is
+// reported whole. Numbers compare by value. Identical documents yield "".
+func (self *Gson) DiffText(other *Gson) (string, error) {
+	a, err := jsonValue(self.data)
+	if err != nil {
+		return "", err
+	}
+	b, err := jsonValue(other.data)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	diffText(&out, nil, a, b)
+	return out.String(), nil
+}
+
+func diffText(out *strings.Builder, path []string, a, b interface{}) {
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, k := range mergedKeys(x, y) {
+			p := append(path, k)
+			xv, inA := x[k]
+			yv, inB := y[k]
+			switch {
+			case !inB:
+				diffLine(out, "-", p)
+			case !inA:
+				diffLine(out, "+", p, yv)
+			default:
+				diffText(out, p, xv, yv)
+			}
+		}
+		return
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(x) || i < len(y); i++ {
+			p := append(path, strconv.Itoa(i))
+			switch {
+			case i >= len(y):
+				diffLine(out, "-", p)
+			case i >= len(x):
+				diffLine(out, "+", p, y[i])
+			default:
+				diffText(out, p, x[i], y[i])
+			}
+		}
+		return
+	}
+	if !valuesEqual(a, b) {
+		diffLine(out, "~", path, a, b)
+	}
+}
+
+func diffLine(out *strings.Builder, op string, path []string, values ...interface{}) {
+	out.WriteString(op)
+	out.WriteByte(' ')
+	if len(path) == 0 {
+		out.WriteString("(root)")
+	} else {
+		out.WriteString(formatPointer(path))
+	}
+	for i, v := range values {
+		if i == 0 {
+			out.WriteString(": ")
+		} else {
+			out.WriteString(" => ")
+		}
+		b, _ := json.Marshal(v)
+		out.Write(b)
+	}
+	out.WriteByte('\n')
+}
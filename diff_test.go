@@ -0,0 +1,39 @@
+package gson
+
+import (
+	"git.egret.io/go/assert"
+	"testing"
+)
+
+func TestDiffText(t *testing.T) {
+	a, err := NewGson([]byte(`{"a":{"b":1,"keep":true},"d":"gone","list":[1,2,3],"kind":[1],"same":1.0}`))
+	assert.Equal(t, nil, err)
+	b, err := NewGson([]byte(`{"a":{"b":2,"keep":true},"c":"new","list":[1,5],"kind":{"x":1},"same":1}`))
+	assert.Equal(t, nil, err)
+
+	text, err := a.DiffText(b)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `~ /a/b: 1 => 2
++ /c: "new"
+- /d
+~ /kind: [1] => {"x":1}
+~ /list/1: 2 => 5
+- /list/2
+`, text)
+
+	text, _ = b.DiffText(a)
+	assert.Equal(t, "~ /a/b: 2 => 1\n- /c\n+ /d: \"gone\"\n~ /kind: {\"x\":1} => [1]\n~ /list/1: 5 => 2\n+ /list/2: 3\n", text)
+
+	text, _ = a.DiffText(a)
+	assert.Equal(t, "", text)
+
+	x, _ := NewGson([]byte(`1`))
+	y, _ := NewGson([]byte(`"1"`))
+	text, _ = x.DiffText(y)
+	assert.Equal(t, "~ (root): 1 => \"1\"\n", text)
+
+	bad := New()
+	bad.Set("f", func() {})
+	_, err = a.DiffText(bad)
+	assert.NotEqual(t, nil, err)
+}
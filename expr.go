@@ -0,0 +1,493 @@
+package gson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Transform evaluates `expr`, written in a small subset of the jq language,
+// against the document and returns the result. The supported syntax is:
+//
+//	.              the input itself
+//	.foo  ."foo"   the member foo of an object (null if absent, or on null)
+//	.["foo"]       the same, for any key
+//	.[2]  .[-1]    an array element, negative indices counting from the end
+//	                (null if out of range, or on null)
+//	.[]            every element of an array, or every member value of an
+//	                object in key order
+//	a | b          b applied to every result of a
+//	[a]            an array of all results of a
+//	{k: a, k2}     an object; each value must yield one result, and a bare
+//	                key k2 stands for k2: .k2
+//	map(a)         the same as [.[] | a]
+//	keys           the sorted keys of an object, or the indices of an array
+//	length         the number of members, elements or characters; 0 for null
+//	(a)            grouping
+//
+// accessors chain as in jq, so `.items[0].name` and `.items[].id` work.
+// An expression that iterates with `[]` outside of `[...]` or `map` yields
+// a stream of results, which Transform returns collected into an array;
+// any other expression yields the single value. Syntax outside this subset
+// is reported with its offset.
+//
+//	names, err := js.Transform(`.users | map({name, city: .address.city})`)
+func (self *Gson) Transform(expr string) (*Gson, error) {
+	p := &exprParser{src: expr}
+	n, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	v, err := jsonValue(self.data)
+	if err != nil {
+		return nil, err
+	}
+	out, err := n.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	if n.stream() {
+		return &Gson{data: deepCopy(append([]interface{}{}, out...))}, nil
+	}
+	return &Gson{data: deepCopy(out[0])}, nil
+}
+
+// exprNode is a parsed Transform expression
+type exprNode interface {
+	// eval returns the results of applying the node to `v`
+	eval(v interface{}) ([]interface{}, error)
+	// stream reports whether the node may yield other than one result
+	stream() bool
+}
+
+type identityNode struct{}
+
+func (identityNode) eval(v interface{}) ([]interface{}, error) { return []interface{}{v}, nil }
+func (identityNode) stream() bool                              { return false }
+
+type pipeNode struct{ left, right exprNode }
+
+func (n pipeNode) eval(v interface{}) ([]interface{}, error) {
+	in, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, e := range in {
+		r, err := n.right.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r...)
+	}
+	return out, nil
+}
+
+func (n pipeNode) stream() bool { return n.left.stream() || n.right.stream() }
+
+type keyNode struct{ key string }
+
+func (n keyNode) eval(v interface{}) ([]interface{}, error) {
+	switch c := v.(type) {
+	case nil:
+		return []interface{}{nil}, nil
+	case map[string]interface{}:
+		return []interface{}{c[n.key]}, nil
+	}
+	return nil, fmt.Errorf("cannot index %s with %q", kindOf(v), n.key)
+}
+
+func (keyNode) stream() bool { return false }
+
+type indexNode struct{ index int }
+
+func (n indexNode) eval(v interface{}) ([]interface{}, error) {
+	switch c := v.(type) {
+	case nil:
+		return []interface{}{nil}, nil
+	case []interface{}:
+		i := n.index
+		if i < 0 {
+			i += len(c)
+		}
+		if i < 0 || i >= len(c) {
+			return []interface{}{nil}, nil
+		}
+		return []interface{}{c[i]}, nil
+	}
+	return nil, fmt.Errorf("cannot index %s with %d", kindOf(v), n.index)
+}
+
+func (indexNode) stream() bool { return false }
+
+type iterateNode struct{}
+
+func (iterateNode) eval(v interface{}) ([]interface{}, error) {
+	switch c := v.(type) {
+	case []interface{}:
+		return c, nil
+	case map[string]interface{}:
+		out := make([]interface{}, 0, len(c))
+		for _, k := range sortedKeys(c) {
+			out = append(out, c[k])
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("cannot iterate over %s", kindOf(v))
+}
+
+func (iterateNode) stream() bool { return true }
+
+type collectNode struct{ inner exprNode }
+
+func (n collectNode) eval(v interface{}) ([]interface{}, error) {
+	out, err := n.inner.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{append([]interface{}{}, out...)}, nil
+}
+
+func (collectNode) stream() bool { return false }
+
+type objectNode struct {
+	keys   []string
+	values []exprNode
+}
+
+func (n objectNode) eval(v interface{}) ([]interface{}, error) {
+	m := make(map[string]interface{}, len(n.keys))
+	for i, k := range n.keys {
+		r, err := n.values[i].eval(v)
+		if err != nil {
+			return nil, err
+		}
+		if len(r) != 1 {
+			return nil, fmt.Errorf("object value for %q yields %d results, not one", k, len(r))
+		}
+		m[k] = r[0]
+	}
+	return []interface{}{m}, nil
+}
+
+func (objectNode) stream() bool { return false }
+
+type keysNode struct{}
+
+func (keysNode) eval(v interface{}) ([]interface{}, error) {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		keys := sortedKeys(c)
+		out := make([]interface{}, len(keys))
+		for i, k := range keys {
+			out[i] = k
+		}
+		return []interface{}{out}, nil
+	case []interface{}:
+		out := make([]interface{}, len(c))
+		for i := range c {
+			out[i] = i
+		}
+		return []interface{}{out}, nil
+	}
+	return nil, fmt.Errorf("%s has no keys", kindOf(v))
+}
+
+func (keysNode) stream() bool { return false }
+
+type lengthNode struct{}
+
+func (lengthNode) eval(v interface{}) ([]interface{}, error) {
+	switch c := v.(type) {
+	case nil:
+		return []interface{}{0}, nil
+	case map[string]interface{}:
+		return []interface{}{len(c)}, nil
+	case []interface{}:
+		return []interface{}{len(c)}, nil
+	case string:
+		return []interface{}{utf8.RuneCountInString(c)}, nil
+	}
+	return nil, fmt.Errorf("%s has no length", kindOf(v))
+}
+
+func (lengthNode) stream() bool { return false }
+
+// exprParser is a recursive descent parser for Transform expressions
+type exprParser struct {
+	src string
+	pos int
+}
+
+func (p *exprParser) parse() (exprNode, error) {
+	n, err := p.pipe()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, p.unexpected()
+	}
+	return n, nil
+}
+
+// pipe parses `term ('|' term)*`
+func (p *exprParser) pipe() (exprNode, error) {
+	n, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept('|') {
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		n = pipeNode{n, right}
+	}
+	return n, nil
+}
+
+// term parses a primary expression followed by accessors
+func (p *exprParser) term() (exprNode, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, p.unexpected()
+	}
+
+	var n exprNode
+	switch c := p.src[p.pos]; {
+	case c == '.':
+		p.pos++
+		n = identityNode{}
+		if p.pos < len(p.src) && p.src[p.pos] != '[' {
+			key, ok, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				n = keyNode{key}
+			} else if p.peek('.') {
+				return nil, p.unexpected()
+			}
+		}
+	case c == '[':
+		p.pos++
+		inner, err := p.pipe()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(']') {
+			return nil, p.unexpected()
+		}
+		n = collectNode{inner}
+	case c == '{':
+		p.pos++
+		obj, err := p.object()
+		if err != nil {
+			return nil, err
+		}
+		n = obj
+	case c == '(':
+		p.pos++
+		inner, err := p.pipe()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(')') {
+			return nil, p.unexpected()
+		}
+		n = inner
+	case isIdentStart(c):
+		start := p.pos
+		name := p.ident()
+		switch name {
+		case "keys":
+			n = keysNode{}
+		case "length":
+			n = lengthNode{}
+		case "map":
+			if !p.accept('(') {
+				return nil, p.unexpected()
+			}
+			inner, err := p.pipe()
+			if err != nil {
+				return nil, err
+			}
+			if !p.accept(')') {
+				return nil, p.unexpected()
+			}
+			n = collectNode{pipeNode{iterateNode{}, inner}}
+		default:
+			return nil, fmt.Errorf("invalid expression %q: unsupported function %q at offset %d", p.src, name, start)
+		}
+	default:
+		return nil, p.unexpected()
+	}
+
+	for {
+		switch {
+		case p.peek('.'):
+			p.pos++
+			key, ok, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, p.unexpected()
+			}
+			n = pipeNode{n, keyNode{key}}
+		case p.peek('['):
+			p.pos++
+			acc, err := p.bracket()
+			if err != nil {
+				return nil, err
+			}
+			n = pipeNode{n, acc}
+		default:
+			return n, nil
+		}
+	}
+}
+
+// bracket parses what follows `[` in an accessor: `]`, an index or a
+// quoted key, and the closing `]`
+func (p *exprParser) bracket() (exprNode, error) {
+	p.skipSpace()
+	if p.accept(']') {
+		return iterateNode{}, nil
+	}
+	var n exprNode
+	if p.peek('"') {
+		key, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		n = keyNode{key}
+	} else {
+		start := p.pos
+		if p.pos < len(p.src) && p.src[p.pos] == '-' {
+			p.pos++
+		}
+		for p.pos < len(p.src) && '0' <= p.src[p.pos] && p.src[p.pos] <= '9' {
+			p.pos++
+		}
+		i, err := strconv.Atoi(p.src[start:p.pos])
+		if err != nil {
+			p.pos = start
+			return nil, p.unexpected()
+		}
+		n = indexNode{i}
+	}
+	if !p.accept(']') {
+		return nil, p.unexpected()
+	}
+	return n, nil
+}
+
+// object parses the entries of an object construction after `{`
+func (p *exprParser) object() (exprNode, error) {
+	var obj objectNode
+	if p.accept('}') {
+		return obj, nil
+	}
+	for {
+		p.skipSpace()
+		key, ok, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, p.unexpected()
+		}
+		var value exprNode = keyNode{key}
+		if p.accept(':') {
+			if value, err = p.term(); err != nil {
+				return nil, err
+			}
+		}
+		obj.keys = append(obj.keys, key)
+		obj.values = append(obj.values, value)
+		if p.accept('}') {
+			return obj, nil
+		}
+		if !p.accept(',') {
+			return nil, p.unexpected()
+		}
+	}
+}
+
+// key parses an identifier or a quoted string, reporting false if there
+// is neither at the current position
+func (p *exprParser) key() (string, bool, error) {
+	if p.pos >= len(p.src) {
+		return "", false, nil
+	}
+	if p.src[p.pos] == '"' {
+		s, err := p.quoted()
+		return s, err == nil, err
+	}
+	if isIdentStart(p.src[p.pos]) {
+		return p.ident(), true, nil
+	}
+	return "", false, nil
+}
+
+func (p *exprParser) ident() string {
+	start := p.pos
+	for p.pos < len(p.src) && (isIdentStart(p.src[p.pos]) || '0' <= p.src[p.pos] && p.src[p.pos] <= '9') {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// quoted parses a JSON string literal
+func (p *exprParser) quoted() (string, error) {
+	start := p.pos
+	for i := p.pos + 1; i < len(p.src); i++ {
+		switch p.src[i] {
+		case '\\':
+			i++
+		case '"':
+			s, err := strconv.Unquote(p.src[start : i+1])
+			if err != nil {
+				return "", fmt.Errorf("invalid expression %q: bad string at offset %d", p.src, start)
+			}
+			p.pos = i + 1
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("invalid expression %q: unterminated string at offset %d", p.src, start)
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.src) && strings.IndexByte(" \t\r\n", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *exprParser) peek(c byte) bool {
+	return p.pos < len(p.src) && p.src[p.pos] == c
+}
+
+// accept consumes `c`, after optional whitespace, if it comes next
+func (p *exprParser) accept(c byte) bool {
+	p.skipSpace()
+	if p.peek(c) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) unexpected() error {
+	if p.pos >= len(p.src) {
+		return fmt.Errorf("invalid expression %q: unexpected end", p.src)
+	}
+	r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+	return fmt.Errorf("invalid expression %q: unexpected %q at offset %d", p.src, r, p.pos)
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
@@ -0,0 +1,80 @@
+package gson
+
+import (
+	"git.egret.io/go/assert"
+	"testing"
+)
+
+func TestTransform(t *testing.T) {
+	js, err := NewGson([]byte(`{
+		"users": [
+			{"name": "ann", "age": 31, "address": {"city": "Oslo"}, "tags": ["a", "b"]},
+			{"name": "bob", "age": 25, "address": {"city": "Rome"}, "tags": []}
+		],
+		"meta": {"total": 2, "odd key": "x"},
+		"empty": null
+	}`))
+	assert.Equal(t, nil, err)
+
+	for expr, expected := range map[string]string{
+		`.`:                           `{"empty":null,"meta":{"odd key":"x","total":2},"users":[{"address":{"city":"Oslo"},"age":31,"name":"ann","tags":["a","b"]},{"address":{"city":"Rome"},"age":25,"name":"bob","tags":[]}]}`,
+		`.meta.total`:                 `2`,
+		`.meta."odd key"`:             `"x"`,
+		`.meta["odd key"]`:            `"x"`,
+		`.missing`:                    `null`,
+		`.empty.deeper[0]`:            `null`,
+		`.users[0].name`:              `"ann"`,
+		`.users[-1].address.city`:     `"Rome"`,
+		`.users[5]`:                   `null`,
+		`.users[].name`:               `["ann","bob"]`,
+		`.users[] | .age`:             `[31,25]`,
+		`.users[0].tags[]`:            `["a","b"]`,
+		`.users[1].tags[]`:            `[]`,
+		`.meta[]`:                     `["x",2]`,
+		`[.users[].name]`:             `["ann","bob"]`,
+		`.users | map(.address.city)`: `["Oslo","Rome"]`,
+		`.users | map({name, city: .address.city})`:          `[{"city":"Oslo","name":"ann"},{"city":"Rome","name":"bob"}]`,
+		`{count: .meta.total, "first user": .users[0].name}`: `{"count":2,"first user":"ann"}`,
+		`.users | length`:                `2`,
+		`.users[0].name | length`:        `3`,
+		`.meta | keys`:                   `["odd key","total"]`,
+		`.users | keys`:                  `[0,1]`,
+		`(.users | map(.tags | length))`: `[2,0]`,
+		`{}`:                             `{}`,
+	} {
+		out, err := js.Transform(expr)
+		assert.Equal(t, nil, err, expr)
+		b, _ := out.Encode()
+		assert.Equal(t, expected, string(b), expr)
+	}
+
+	// results don't alias the document or each other
+	out, _ := js.Transform(`{a: .meta, b: .meta}`)
+	out.Get("a").Set("total", 9)
+	assert.Equal(t, 2, out.GetPath("b", "total").MustInt())
+	assert.Equal(t, 2, js.GetPath("meta", "total").MustInt())
+}
+
+func TestTransformErrors(t *testing.T) {
+	js, _ := NewGson([]byte(`{"users":[{"name":"ann"},{"name":"bob"}],"n":1}`))
+
+	for expr, expected := range map[string]string{
+		``:                       `invalid expression "": unexpected end`,
+		`.users[`:                `invalid expression ".users[": unexpected end`,
+		`..name`:                 `invalid expression "..name": unexpected '.' at offset 1`,
+		`.users | select(.name)`: `invalid expression ".users | select(.name)": unsupported function "select" at offset 9`,
+		`.a == 1`:                `invalid expression ".a == 1": unexpected '=' at offset 3`,
+		`{a: .b | .c}`:           `invalid expression "{a: .b | .c}": unexpected '|' at offset 7`,
+		`."unterminated`:         `invalid expression ".\"unterminated": unterminated string at offset 1`,
+		`.users.name`:            `cannot index array with "name"`,
+		`.n[0]`:                  `cannot index number with 0`,
+		`.n[]`:                   `cannot iterate over number`,
+		`.n | keys`:              `number has no keys`,
+		`{x: .users[]}`:          `object value for "x" yields 2 results, not one`,
+	} {
+		_, err := js.Transform(expr)
+		if assert.NotEqual(t, nil, err, expr); err != nil {
+			assert.Equal(t, expected, err.Error(), expr)
+		}
+	}
+}
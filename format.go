@@ -0,0 +1,78 @@
+package gson
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MatchesFormat reports whether the node, which must be a string,
+// conforms to the named format, in the spirit of JSON Schema's `format`
+// keyword. The built-in formats are:
+//
+//   - "email": a bare address such as "ann@example.com" (no display name)
+//   - "uri": an absolute URI, one that has a scheme
+//   - "ipv4": a dotted-decimal IPv4 address
+//   - "ipv6": an IPv6 address in colon notation
+//   - "date": an RFC 3339 full-date, "2006-01-02"
+//   - "datetime": an RFC 3339 date-time, "2006-01-02T15:04:05Z07:00"
+//   - "uuid": a hyphenated UUID in either letter case
+//
+// more can be added with RegisterFormat. Naming a format that is neither
+// built in nor registered is an error.
+func (self *Gson) MatchesFormat(format string) (bool, error) {
+	formatsMu.RLock()
+	check, ok := formats[format]
+	formatsMu.RUnlock()
+	if !ok {
+		return false, fmt.Errorf("unknown format %q", format)
+	}
+	s, err := self.String()
+	if err != nil {
+		return false, err
+	}
+	return check(s), nil
+}
+
+// RegisterFormat makes `check` available to MatchesFormat under `name`,
+// replacing any format of that name, built-in ones included. It is safe
+// to call concurrently with MatchesFormat.
+func RegisterFormat(name string, check func(s string) bool) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats[name] = check
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]func(string) bool{
+		"email": func(s string) bool {
+			addr, err := mail.ParseAddress(s)
+			return err == nil && addr.Name == "" && addr.Address == s
+		},
+		"uri": func(s string) bool {
+			u, err := url.Parse(s)
+			return err == nil && u.Scheme != ""
+		},
+		"ipv4": func(s string) bool {
+			ip := net.ParseIP(s)
+			return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+		},
+		"ipv6": func(s string) bool {
+			return net.ParseIP(s) != nil && strings.Contains(s, ":")
+		},
+		"date": func(s string) bool {
+			_, err := time.Parse("2006-01-02", s)
+			return err == nil
+		},
+		"datetime": func(s string) bool {
+			_, err := time.Parse(time.RFC3339, s)
+			return err == nil
+		},
+		"uuid": isUUID,
+	}
+)
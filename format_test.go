@@ -0,0 +1,70 @@
+package gson
+
+import (
+	"git.egret.io/go/assert"
+	"strings"
+	"testing"
+)
+
+func TestMatchesFormat(t *testing.T) {
+	cases := map[string]map[string]bool{
+		"email": {
+			"ann@example.com":       true,
+			"Ann <ann@example.com>": false,
+			"no-at-sign":            false,
+			"ann@":                  false,
+		},
+		"uri": {
+			"https://example.com/a?b=c": true,
+			"mailto:ann@example.com":    true,
+			"/relative/path":            false,
+		},
+		"ipv4": {
+			"192.168.0.1":    true,
+			"256.1.1.1":      false,
+			"::ffff:1.2.3.4": false,
+		},
+		"ipv6": {
+			"::1":         true,
+			"2001:db8::1": true,
+			"192.168.0.1": false,
+		},
+		"date": {
+			"2024-02-29": true,
+			"2023-02-29": false,
+			"2024-2-1":   false,
+		},
+		"datetime": {
+			"2024-01-02T03:04:05Z":          true,
+			"2024-01-02T03:04:05.123+02:00": true,
+			"2024-01-02 03:04:05":           false,
+		},
+		"uuid": {
+			"123e4567-e89b-12d3-a456-426614174000": true,
+			"123E4567-E89B-12D3-A456-426614174000": true,
+			"123e4567e89b12d3a456426614174000":     false,
+		},
+	}
+	for format, values := range cases {
+		for s, expected := range values {
+			ok, err := (&Gson{data: s}).MatchesFormat(format)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, expected, ok, format, s)
+		}
+	}
+
+	_, err := (&Gson{data: "x"}).MatchesFormat("hostname")
+	assert.Equal(t, `unknown format "hostname"`, err.Error())
+
+	_, err = (&Gson{data: 1}).MatchesFormat("email")
+	assert.NotEqual(t, nil, err)
+
+	RegisterFormat("lowercase", func(s string) bool {
+		return s == strings.ToLower(s)
+	})
+	ok, err := (&Gson{data: "abc"}).MatchesFormat("lowercase")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, ok)
+	ok, _ = (&Gson{data: "aBc"}).MatchesFormat("lowercase")
+	assert.Equal(t, false, ok)
+}
@@ -0,0 +1,78 @@
+package gson
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DecodeMap type asserts `g` to a `map` and decodes every value into `V`
+//
+// useful for objects with dynamic keys but uniformly typed values:
+//
+//	counts, err := DecodeMap[int](js.Get("counts"))
+func DecodeMap[V any](g *Gson) (map[string]V, error) {
+	m, err := g.Map()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]V, len(m))
+	for k, v := range m {
+		var dst V
+		if err := decodeValue(v, &dst); err != nil {
+			return nil, fmt.Errorf("decoding key %q: %w", k, err)
+		}
+		out[k] = dst
+	}
+	return out, nil
+}
+
+// DecodeSlice type asserts `g` to an `array` and decodes every element into `T`
+//
+// this generalizes StringArray to any element type, including structs:
+//
+//	users, err := DecodeSlice[User](js.Get("users"))
+func DecodeSlice[T any](g *Gson) ([]T, error) {
+	a, err := g.Array()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]T, len(a))
+	for i, v := range a {
+		if err := decodeValue(v, &out[i]); err != nil {
+			return nil, fmt.Errorf("decoding index %d: %w", i, err)
+		}
+	}
+	return out, nil
+}
+
+// decodeValue stores the decoded value `v` into the value pointed to by `dst`
+func decodeValue(v interface{}, dst interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dst)
+}
+
+// GetAs returns the value for `key` in the `map` representation of `g`
+// decoded into `T`, or `def` if the key is missing, null, or can't be
+// decoded into `T`
+//
+// useful for reading typed settings with fallbacks in one expression:
+//
+//	name := GetAs[string](cfg, "name", "anon")
+//	limits := GetAs[[]int](cfg, "limits", nil)
+func GetAs[T any](g *Gson, key string, def T) T {
+	v, ok := g.CheckGet(key)
+	if !ok || v.data == nil {
+		return def
+	}
+	if t, ok := expandAll(v.data).(T); ok {
+		return t
+	}
+	var dst T
+	if err := decodeValue(v.data, &dst); err != nil {
+		return def
+	}
+	return dst
+}
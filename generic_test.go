@@ -0,0 +1,78 @@
+package gson
+
+import (
+	"encoding/json"
+	"git.egret.io/go/assert"
+	"strings"
+	"testing"
+)
+
+func TestDecodeMap(t *testing.T) {
+	js, err := NewGson([]byte(`{"counts":{"a":1,"b":2},"users":{"x":{"name":"ann"}},"bad":{"a":1,"b":"two"}}`))
+	assert.Equal(t, nil, err)
+
+	counts, err := DecodeMap[int](js.Get("counts"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, counts)
+
+	type user struct {
+		Name string `json:"name"`
+	}
+	users, err := DecodeMap[user](js.Get("users"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "ann", users["x"].Name)
+
+	_, err = DecodeMap[int](js.Get("bad"))
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, true, strings.Contains(err.Error(), `"b"`))
+
+	_, err = DecodeMap[int](js.Get("missing"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestDecodeSlice(t *testing.T) {
+	js, err := NewGson([]byte(`{"ints":[1,2,3],"users":[{"name":"ann"},{"name":"bob"}],"bad":[1,"two"]}`))
+	assert.Equal(t, nil, err)
+
+	ints, err := DecodeSlice[int64](js.Get("ints"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64{1, 2, 3}, ints)
+
+	type user struct {
+		Name string `json:"name"`
+	}
+	users, err := DecodeSlice[user](js.Get("users"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []user{{"ann"}, {"bob"}}, users)
+
+	_, err = DecodeSlice[int](js.Get("bad"))
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, true, strings.Contains(err.Error(), "index 1"))
+
+	_, err = DecodeSlice[int](js)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestGetAs(t *testing.T) {
+	js, err := NewGson([]byte(`{"name":"svc","port":8080,"ratio":0.5,"debug":true,"tags":["a","b"],"limits":{"cpu":2},"none":null}`))
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, "svc", GetAs[string](js, "name", "anon"))
+	assert.Equal(t, "anon", GetAs[string](js, "missing", "anon"))
+	assert.Equal(t, "anon", GetAs[string](js, "port", "anon"))
+	assert.Equal(t, "anon", GetAs[string](js, "none", "anon"))
+	assert.Equal(t, 8080, GetAs[int](js, "port", 0))
+	assert.Equal(t, 7, GetAs[int](js, "ratio", 7))
+	assert.Equal(t, 0.5, GetAs[float64](js, "ratio", 0))
+	assert.Equal(t, true, GetAs[bool](js, "debug", false))
+	assert.Equal(t, []string{"a", "b"}, GetAs[[]string](js, "tags", nil))
+	assert.Equal(t, map[string]int{"cpu": 2}, GetAs[map[string]int](js, "limits", nil))
+	assert.Equal(t, json.Number("8080"), GetAs[json.Number](js, "port", ""))
+
+	assert.Equal(t, "x", GetAs[string](js.Get("name"), "name", "x"))
+
+	lazy, err := NewLazy([]byte(`{"limits":{"cpu":[2]}}`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[string]interface{}{"cpu": []interface{}{json.Number("2")}}, GetAs[interface{}](lazy, "limits", nil))
+	assert.Equal(t, map[string][]int{"cpu": {2}}, GetAs[map[string][]int](lazy, "limits", nil))
+}
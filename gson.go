@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log"
 	"reflect"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // returns the current implementation version
@@ -15,8 +18,27 @@ func Version() string {
 	return "0.1.0"
 }
 
+// Gson wraps an arbitrary decoded JSON value.
+//
+// Navigation methods (Get, GetIndex, GetPath, CheckGet) return new wrappers
+// around the nested value rather than copies of it: objects and arrays are
+// shared with the parent document, so Set and Del on a navigated object
+// modify the parent in place. A lookup that misses returns a wrapper around
+// nil which is detached from the document, and mutations on it are lost;
+// use Edit to obtain a node that is guaranteed to be wired into the document.
 type Gson struct {
 	data interface{}
+
+	// set on documents created by NewGsonLinked
+	linked bool
+	parent *Gson
+
+	// set on documents created by NewStrictNav
+	nav     *navState
+	navPath []string
+
+	// set on documents created by NewOrdered
+	order *keyOrder
 }
 
 // NewGson returns a pointer to a new `Gson` object
@@ -30,6 +52,21 @@ func NewGson(body []byte) (*Gson, error) {
 	return self, nil
 }
 
+// NewFromRaw returns a pointer to a new `Gson` object after unmarshaling
+// `raw`, as NewGson does
+//
+// useful for exploring a field left undecoded by json.Unmarshal:
+//
+//	var event struct {
+//		Type    string          `json:"type"`
+//		Payload json.RawMessage `json:"payload"`
+//	}
+//	json.Unmarshal(body, &event)
+//	payload, err := gson.NewFromRaw(event.Payload)
+func NewFromRaw(raw json.RawMessage) (*Gson, error) {
+	return NewGson(raw)
+}
+
 // NewFromReader returns a *Gson by decoding from an io.Reader
 func NewFromReader(r io.Reader) (*Gson, error) {
 	self := new(Gson)
@@ -39,6 +76,35 @@ func NewFromReader(r io.Reader) (*Gson, error) {
 	return self, err
 }
 
+// NewGsonFloat is like NewGson but decodes numbers as `float64` rather than
+// `json.Number`, trading precision for compatibility with code that type
+// switches on float64
+func NewGsonFloat(body []byte) (*Gson, error) {
+	return NewFromReaderFloat(bytes.NewReader(body))
+}
+
+// NewFromReaderFloat is like NewFromReader but decodes numbers as `float64`
+// rather than `json.Number`
+func NewFromReaderFloat(r io.Reader) (*Gson, error) {
+	self := new(Gson)
+	err := json.NewDecoder(r).Decode(&self.data)
+	if err != nil {
+		return nil, err
+	}
+	return self, nil
+}
+
+// NewGsonLinked is like NewGson but every `Gson` object navigated to from
+// the result remembers where it came from, enabling Parent and Root
+func NewGsonLinked(body []byte) (*Gson, error) {
+	self, err := NewGson(body)
+	if err != nil {
+		return nil, err
+	}
+	self.linked = true
+	return self, nil
+}
+
 // New returns a pointer to a new, empty `Gson` object
 func New() *Gson {
 	return &Gson{
@@ -51,6 +117,26 @@ func (self *Gson) Interface() interface{} {
 	return self.data
 }
 
+// ShallowClone returns a pointer to a new `Gson` object holding a copy of
+// its top-level `map` or `array` container. Nested values are shared, not
+// copied: adding or removing top-level keys on the clone leaves the
+// original alone, but modifying a nested object through either one is
+// visible in both.
+func (self *Gson) ShallowClone() *Gson {
+	c := &Gson{data: self.data}
+	switch v := self.data.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = e
+		}
+		c.data = m
+	case []interface{}:
+		c.data = append([]interface{}(nil), v...)
+	}
+	return c
+}
+
 // Encode returns its marshaled data as `[]byte`
 func (self *Gson) Encode() ([]byte, error) {
 	return self.MarshalJSON()
@@ -58,11 +144,89 @@ func (self *Gson) Encode() ([]byte, error) {
 
 // EncodePretty returns its marshaled data as `[]byte` with indentation
 func (self *Gson) EncodePretty() ([]byte, error) {
+	if self.order != nil {
+		b, err := self.order.marshal(self.data)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, b, "", "  "); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
 	return json.MarshalIndent(&self.data, "", "  ")
 }
 
+// WriteTo writes its marshaled data to `w`, implementing io.WriterTo
+func (self *Gson) WriteTo(w io.Writer) (int64, error) {
+	b, err := self.MarshalJSON()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+// EncodedSize returns the length in bytes of its marshaled data, as Encode
+// would produce it, without keeping the encoded output around
+func (self *Gson) EncodedSize() (int, error) {
+	var w countingWriter
+	if err := json.NewEncoder(&w).Encode(&self.data); err != nil {
+		return 0, err
+	}
+	// json.Encoder terminates each value with a newline
+	return w.n - 1, nil
+}
+
+// countingWriter discards what is written to it, counting the bytes
+type countingWriter struct {
+	n int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += len(p)
+	return len(p), nil
+}
+
+// Reader returns an `io.Reader` yielding its marshaled data; an encoding
+// error is returned by the first Read
+//
+// useful for request bodies:
+//
+//	http.Post(url, "application/json", js.Reader())
+func (self *Gson) Reader() io.Reader {
+	return newReader(self.Encode())
+}
+
+// PrettyReader is like Reader but yields the indented form of EncodePretty
+func (self *Gson) PrettyReader() io.Reader {
+	return newReader(self.EncodePretty())
+}
+
+func newReader(b []byte, err error) io.Reader {
+	if err != nil {
+		return errReader{err}
+	}
+	return bytes.NewReader(b)
+}
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
 // Implements the json.Marshaler interface.
+//
+// numbers are always encoded bare, whether they were parsed (and so held as
+// `json.Number`) or set as plain Go integers and floats.
 func (self *Gson) MarshalJSON() ([]byte, error) {
+	if self.order != nil {
+		return self.order.marshal(self.data)
+	}
 	return json.Marshal(&self.data)
 }
 
@@ -74,6 +238,43 @@ func (self *Gson) Set(key string, val interface{}) {
 		return
 	}
 	m[key] = val
+	if self.order != nil {
+		self.order.add(m, key)
+	}
+}
+
+// SetRoot replaces the whole document with `val`, which may be any value
+// (not only a `map`), e.g. an array produced by a transform
+func (self *Gson) SetRoot(val interface{}) {
+	self.data = val
+}
+
+// SetJSON parses `jsonValue` and sets the resulting structure under `key`
+// (as Set would), failing if `jsonValue` is not valid JSON
+func (self *Gson) SetJSON(key string, jsonValue []byte) error {
+	if !json.Valid(jsonValue) {
+		return errors.New("invalid JSON")
+	}
+	v, err := NewGson(jsonValue)
+	if err != nil {
+		return err
+	}
+	self.Set(key, v.data)
+	return nil
+}
+
+// SetJSONPath parses `jsonValue` and writes the resulting structure at
+// `branch` (as SetPath would), failing if `jsonValue` is not valid JSON
+func (self *Gson) SetJSONPath(branch []string, jsonValue []byte) error {
+	if !json.Valid(jsonValue) {
+		return errors.New("invalid JSON")
+	}
+	v, err := NewGson(jsonValue)
+	if err != nil {
+		return err
+	}
+	self.SetPath(branch, v.data)
+	return nil
 }
 
 // SetPath modifies `Gson`, recursively checking/creating map keys for the supplied path,
@@ -85,6 +286,7 @@ func (self *Gson) SetPath(branch []string, val interface{}) {
 	}
 
 	// in order to insert our branch, we need map[string]interface{}
+	self.data = expandLazy(self.data)
 	if _, ok := (self.data).(map[string]interface{}); !ok {
 		// have to replace with something suitable
 		self.data = make(map[string]interface{})
@@ -102,6 +304,7 @@ func (self *Gson) SetPath(branch []string, val interface{}) {
 		}
 
 		// make sure the value is the right sort of thing
+		expandMember(curr, b)
 		if _, ok := curr[b].(map[string]interface{}); !ok {
 			// have to replace with something suitable
 			n := make(map[string]interface{})
@@ -115,6 +318,121 @@ func (self *Gson) SetPath(branch []string, val interface{}) {
 	curr[branch[len(branch)-1]] = val
 }
 
+// SetPathSafe is like SetPath but never replaces an existing value to make
+// room for the path: it fails, leaving the document unchanged, if the root
+// or an intermediate key along `branch` holds something other than an
+// object (null included)
+func (self *Gson) SetPathSafe(branch []string, val interface{}) error {
+	if len(branch) == 0 {
+		self.data = val
+		return nil
+	}
+
+	if self.data == nil {
+		self.data = make(map[string]interface{})
+	}
+	self.data = expandLazy(self.data)
+	curr, ok := (self.data).(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("root is %s, not an object", kindOf(self.data))
+	}
+
+	for i, b := range branch[:len(branch)-1] {
+		if _, ok := curr[b]; !ok {
+			n := make(map[string]interface{})
+			curr[b] = n
+			curr = n
+			continue
+		}
+		v := expandMember(curr, b)
+		if curr, ok = v.(map[string]interface{}); !ok {
+			return fmt.Errorf("%s is %s, not an object", formatPointer(branch[:i+1]), kindOf(v))
+		}
+	}
+
+	curr[branch[len(branch)-1]] = val
+	return nil
+}
+
+// Edit returns a pointer to the live `Gson` object found at `branch`,
+// creating (or replacing) intermediate maps along the way just like SetPath.
+//
+// unlike GetPath, Set and Del calls on the result always affect this document:
+//
+//	js.Edit("config", "limits").Set("max", 10)
+func (self *Gson) Edit(branch ...string) *Gson {
+	if len(branch) == 0 {
+		return self
+	}
+
+	self.data = expandLazy(self.data)
+	if _, ok := (self.data).(map[string]interface{}); !ok {
+		self.data = make(map[string]interface{})
+	}
+	curr := self
+
+	for _, b := range branch {
+		m := curr.data.(map[string]interface{})
+		if _, ok := m[b]; ok {
+			expandMember(m, b)
+		}
+		n, ok := m[b].(map[string]interface{})
+		if !ok {
+			n = make(map[string]interface{})
+			m[b] = n
+		}
+		curr = curr.child(n)
+	}
+
+	return curr
+}
+
+// AppendPath appends `values` to the array at `branch`, creating the path
+// like SetPath and starting a new array when the final key is absent. It
+// fails if the final key already holds something other than an array.
+//
+// useful for accumulating nested lists:
+//
+//	js.AppendPath([]string{"result", "errors"}, "bad input")
+func (self *Gson) AppendPath(branch []string, values ...interface{}) error {
+	self.data = expandLazy(self.data)
+	if len(branch) == 0 {
+		a, ok := (self.data).([]interface{})
+		if !ok && self.data != nil {
+			return errors.New("append target is not an array")
+		}
+		self.data = append(a, values...)
+		return nil
+	}
+
+	// check the final key before creating anything, so that a failed call
+	// leaves the document as it was
+	key := branch[len(branch)-1]
+	m, _ := (self.data).(map[string]interface{})
+	for _, b := range branch[:len(branch)-1] {
+		if m == nil {
+			break
+		}
+		if _, ok := m[b]; ok {
+			expandMember(m, b)
+		}
+		m, _ = m[b].(map[string]interface{})
+	}
+	var a []interface{}
+	if _, ok := m[key]; ok {
+		if a, ok = expandMember(m, key).([]interface{}); !ok {
+			return fmt.Errorf("append target %q is not an array", key)
+		}
+	}
+
+	if _, ok := (self.data).(map[string]interface{}); !ok {
+		self.data = make(map[string]interface{})
+	}
+	m = self.Edit(branch[:len(branch)-1]...).data.(map[string]interface{})
+	m[key] = append(a, values...)
+	return nil
+}
+
 // Del modifies `Gson` map by deleting `key` if it is present.
 func (self *Gson) Del(key string) {
 	m, err := self.Map()
@@ -122,6 +440,9 @@ func (self *Gson) Del(key string) {
 		return
 	}
 	delete(m, key)
+	if self.order != nil {
+		self.order.remove(m, key)
+	}
 }
 
 // Get returns a pointer to a new `Gson` object
@@ -132,11 +453,14 @@ func (self *Gson) Del(key string) {
 func (self *Gson) Get(key string) *Gson {
 	m, err := self.Map()
 	if err == nil {
-		if val, ok := m[key]; ok {
-			return &Gson{val}
+		if _, ok := m[key]; ok {
+			return self.step(key, expandMember(m, key))
 		}
+		self.fail(key, "key not found")
+	} else if self.nav != nil {
+		self.fail(key, "cannot look up key in %s", kindOf(self.data))
 	}
-	return &Gson{nil}
+	return self.step(key, nil)
 }
 
 // GetPath searches for the item as specified by the branch
@@ -158,13 +482,17 @@ func (self *Gson) GetPath(branch ...string) *Gson {
 // a json array instead of a json object:
 //    js.Get("top_level").Get("array").GetIndex(1).Get("key").Int()
 func (self *Gson) GetIndex(index int) *Gson {
+	seg := strconv.Itoa(index)
 	a, err := self.Array()
 	if err == nil {
 		if len(a) > index {
-			return &Gson{a[index]}
+			return self.step(seg, expandElem(a, index))
 		}
+		self.fail(seg, "index out of range (length %d)", len(a))
+	} else if self.nav != nil {
+		self.fail(seg, "cannot index %s", kindOf(self.data))
 	}
-	return &Gson{nil}
+	return self.step(seg, nil)
 }
 
 // CheckGet returns a pointer to a new `Gson` object and
@@ -177,15 +505,65 @@ func (self *Gson) GetIndex(index int) *Gson {
 func (self *Gson) CheckGet(key string) (*Gson, bool) {
 	m, err := self.Map()
 	if err == nil {
-		if val, ok := m[key]; ok {
-			return &Gson{val}, true
+		if _, ok := m[key]; ok {
+			return self.step(key, expandMember(m, key)), true
 		}
 	}
 	return nil, false
 }
 
+// Parent returns the `Gson` object this one was navigated from, or nil
+// for the root and for documents not created by NewGsonLinked
+func (self *Gson) Parent() *Gson {
+	return self.parent
+}
+
+// Root returns the top-level `Gson` object this one was navigated from
+// (itself when it has no parent)
+//
+// useful for relative lookups during deep processing:
+//
+//	id := node.Root().Get("id").MustString()
+func (self *Gson) Root() *Gson {
+	root := self
+	for root.parent != nil {
+		root = root.parent
+	}
+	return root
+}
+
+// child wraps a value found below self, carrying over its navigation mode
+func (self *Gson) child(val interface{}) *Gson {
+	c := &Gson{data: expandLazy(val), nav: self.nav, navPath: self.navPath, order: self.order}
+	if self.linked {
+		c.linked = true
+		c.parent = self
+	}
+	return c
+}
+
+// IsEmpty reports whether the node holds nothing meaningful: nil (a JSON
+// null or a missed lookup), an empty object, an empty array or the empty
+// string. Every other value, including the number 0 and false, is not
+// empty. Nested values are not inspected, so `{"a":null}` and `[[]]` are
+// not empty either.
+func (self *Gson) IsEmpty() bool {
+	switch v := self.data.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	case string:
+		return v == ""
+	}
+	return false
+}
+
 // Map type asserts to `map`
 func (self *Gson) Map() (map[string]interface{}, error) {
+	self.data = expandLazy(self.data)
 	if m, ok := (self.data).(map[string]interface{}); ok {
 		return m, nil
 	}
@@ -194,6 +572,7 @@ func (self *Gson) Map() (map[string]interface{}, error) {
 
 // Array type asserts to an `array`
 func (self *Gson) Array() ([]interface{}, error) {
+	self.data = expandLazy(self.data)
 	if a, ok := (self.data).([]interface{}); ok {
 		return a, nil
 	}
@@ -216,6 +595,29 @@ func (self *Gson) String() (string, error) {
 	return "", errors.New("type assertion to string failed")
 }
 
+// AsString returns a best-effort `string` rendering of any node: strings
+// as themselves, numbers and bools in their JSON form, null (or a missing
+// node) as "", and objects and arrays as compact JSON. It never fails.
+//
+// useful for building human-readable output from mixed-type nodes:
+//
+//	row = append(row, js.Get("value").AsString())
+func (self *Gson) AsString() string {
+	switch v := self.data.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+	b, err := json.Marshal(self.data)
+	if err != nil {
+		return fmt.Sprint(self.data)
+	}
+	return string(b)
+}
+
 // Bytes type asserts to `[]byte`
 func (self *Gson) Bytes() ([]byte, error) {
 	if s, ok := (self.data).(string); ok {
@@ -224,6 +626,16 @@ func (self *Gson) Bytes() ([]byte, error) {
 	return nil, errors.New("type assertion to []byte failed")
 }
 
+// JSONBytes returns the JSON encoding of the node, whatever its kind; unlike
+// Bytes it does not require a string and yields quoted output for one
+//
+// useful for extracting a raw subtree:
+//
+//	raw, err := js.GetPath("spec", "template").JSONBytes()
+func (self *Gson) JSONBytes() ([]byte, error) {
+	return json.Marshal(&self.data)
+}
+
 // StringArray type asserts to an `array` of `string`
 func (self *Gson) StringArray() ([]string, error) {
 	arr, err := self.Array()
@@ -458,11 +870,172 @@ func (self *Gson) MustUint64(args ...uint64) uint64 {
 	return def
 }
 
+// MapOK is like Map but reports success as a `bool`
+func (self *Gson) MapOK() (map[string]interface{}, bool) {
+	v, err := self.Map()
+	return v, err == nil
+}
+
+// ArrayOK is like Array but reports success as a `bool`
+func (self *Gson) ArrayOK() ([]interface{}, bool) {
+	v, err := self.Array()
+	return v, err == nil
+}
+
+// BoolOK is like Bool but reports success as a `bool`
+func (self *Gson) BoolOK() (bool, bool) {
+	v, err := self.Bool()
+	return v, err == nil
+}
+
+// StringOK is like String but reports success as a `bool`
+//
+// useful in if statements:
+//
+//	if s, ok := js.Get("name").StringOK(); ok {
+//		fmt.Println(s)
+//	}
+func (self *Gson) StringOK() (string, bool) {
+	v, err := self.String()
+	return v, err == nil
+}
+
+// StringArrayOK is like StringArray but reports success as a `bool`
+func (self *Gson) StringArrayOK() ([]string, bool) {
+	v, err := self.StringArray()
+	return v, err == nil
+}
+
+// IntOK is like Int but reports success as a `bool`
+func (self *Gson) IntOK() (int, bool) {
+	v, err := self.Int()
+	return v, err == nil
+}
+
+// Int64OK is like Int64 but reports success as a `bool`
+func (self *Gson) Int64OK() (int64, bool) {
+	v, err := self.Int64()
+	return v, err == nil
+}
+
+// Uint64OK is like Uint64 but reports success as a `bool`
+func (self *Gson) Uint64OK() (uint64, bool) {
+	v, err := self.Uint64()
+	return v, err == nil
+}
+
+// Float64OK is like Float64 but reports success as a `bool`
+func (self *Gson) Float64OK() (float64, bool) {
+	v, err := self.Float64()
+	return v, err == nil
+}
+
+// StringOrElse is like MustString but computes its default lazily: `fn`
+// is only called when the value isn't a `string`
+//
+// useful when the fallback is expensive to produce:
+//
+//	name := js.Get("name").StringOrElse(func() string {
+//		return lookupName(id)
+//	})
+func (self *Gson) StringOrElse(fn func() string) string {
+	if v, err := self.String(); err == nil {
+		return v
+	}
+	return fn()
+}
+
+// IntOrElse is like MustInt but only calls `fn` when coercion fails
+func (self *Gson) IntOrElse(fn func() int) int {
+	if v, err := self.Int(); err == nil {
+		return v
+	}
+	return fn()
+}
+
+// Int64OrElse is like MustInt64 but only calls `fn` when coercion fails
+func (self *Gson) Int64OrElse(fn func() int64) int64 {
+	if v, err := self.Int64(); err == nil {
+		return v
+	}
+	return fn()
+}
+
+// Uint64OrElse is like MustUint64 but only calls `fn` when coercion fails
+func (self *Gson) Uint64OrElse(fn func() uint64) uint64 {
+	if v, err := self.Uint64(); err == nil {
+		return v
+	}
+	return fn()
+}
+
+// Float64OrElse is like MustFloat64 but only calls `fn` when coercion fails
+func (self *Gson) Float64OrElse(fn func() float64) float64 {
+	if v, err := self.Float64(); err == nil {
+		return v
+	}
+	return fn()
+}
+
+// BoolOrElse is like MustBool but only calls `fn` when coercion fails
+func (self *Gson) BoolOrElse(fn func() bool) bool {
+	if v, err := self.Bool(); err == nil {
+		return v
+	}
+	return fn()
+}
+
+// MustDuration guarantees the return of a `time.Duration` (with optional default)
+//
+// useful when you explicitly want a `time.Duration` in a single value return context:
+//
+//	client.Timeout = js.Get("timeout").MustDuration(30 * time.Second)
+func (self *Gson) MustDuration(args ...time.Duration) time.Duration {
+	var def time.Duration
+
+	switch len(args) {
+	case 0:
+	case 1:
+		def = args[0]
+	default:
+		log.Panicf("MustDuration() received too many arguments %d", len(args))
+	}
+
+	d, err := self.Duration()
+	if err == nil {
+		return d
+	}
+
+	return def
+}
+
+// MustUUIDString guarantees the return of a validated UUID `string` (with optional default)
+//
+// useful when you explicitly want an identifier in a single value return context:
+//
+//	id := js.Get("id").MustUUIDString()
+func (self *Gson) MustUUIDString(args ...string) string {
+	var def string
+
+	switch len(args) {
+	case 0:
+	case 1:
+		def = args[0]
+	default:
+		log.Panicf("MustUUIDString() received too many arguments %d", len(args))
+	}
+
+	s, err := self.UUIDString()
+	if err == nil {
+		return s
+	}
+
+	return def
+}
+
 // Implements the json.Unmarshaler interface.
 func (self *Gson) UnmarshalJSON(p []byte) error {
-	dec := json.NewDecoder(bytes.NewBuffer(p))
-	dec.UseNumber()
-	return dec.Decode(&self.data)
+	return decodeBytes(p, &self.data)
 }
 
 // Float64 coerces into a float64
@@ -525,3 +1098,47 @@ func (self *Gson) Uint64() (uint64, error) {
 	}
 	return 0, errors.New("invalid value type")
 }
+
+// Duration coerces into a time.Duration: strings are parsed with
+// time.ParseDuration (e.g. "30s", "1h5m") and numbers are taken as a count
+// of nanoseconds, matching time.Duration's own unit
+func (self *Gson) Duration() (time.Duration, error) {
+	if s, ok := (self.data).(string); ok {
+		return time.ParseDuration(s)
+	}
+	i, err := self.Int64()
+	return time.Duration(i), err
+}
+
+// UUIDString type asserts to `string` and validates it as a UUID in the
+// canonical 8-4-4-4-12 hexadecimal form, returning it lowercased
+func (self *Gson) UUIDString() (string, error) {
+	s, err := self.String()
+	if err != nil {
+		return "", err
+	}
+	if !isUUID(s) {
+		return "", errors.New("invalid UUID " + strconv.Quote(s))
+	}
+	return strings.ToLower(s), nil
+}
+
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"git.egret.io/go/assert"
+	"io"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func TestGsonson(t *testing.T) {
@@ -182,6 +184,11 @@ func TestSetPath(t *testing.T) {
 	s, err := js.GetPath("foo", "bar").String()
 	assert.Equal(t, nil, err)
 	assert.Equal(t, "baz", s)
+
+	lazy, _ := NewLazy([]byte(`{"foo":{"keep":1,"bar":{"old":true}}}`))
+	lazy.SetPath([]string{"foo", "bar", "baz"}, 2)
+	b, _ := lazy.Encode()
+	assert.Equal(t, `{"foo":{"bar":{"baz":2,"old":true},"keep":1}}`, string(b))
 }
 
 func TestSetPathNoPath(t *testing.T) {
@@ -332,3 +339,465 @@ func TestSimplejsonGo11(t *testing.T) {
 	assert.Equal(t, js.Get("test").Get("bignum").MustInt64(), int64(9223372036854775807))
 	assert.Equal(t, js.Get("test").Get("uint64").MustUint64(), uint64(18446744073709551615))
 }
+
+func TestEdit(t *testing.T) {
+	js, err := NewGson([]byte(`{"a":{"b":1},"c":"scalar"}`))
+	assert.Equal(t, nil, err)
+
+	// a missed lookup is detached, so the Set is lost
+	js.GetPath("x", "y").Set("z", 1)
+	_, ok := js.CheckGet("x")
+	assert.Equal(t, false, ok)
+
+	js.Edit("x", "y").Set("z", 1)
+	assert.Equal(t, 1, js.GetPath("x", "y", "z").MustInt())
+
+	js.Edit("a").Set("d", 2)
+	assert.Equal(t, 1, js.GetPath("a", "b").MustInt())
+	assert.Equal(t, 2, js.GetPath("a", "d").MustInt())
+
+	js.Edit("c").Set("e", true)
+	assert.Equal(t, true, js.GetPath("c", "e").MustBool())
+
+	assert.Equal(t, js, js.Edit())
+
+	lazy, _ := NewLazy([]byte(`{"a":{"keep":1,"b":{"c":2}}}`))
+	lazy.Edit("a", "b").Set("x", 3)
+	lazy.Edit("a").Set("y", 4)
+	b, _ := lazy.Encode()
+	assert.Equal(t, `{"a":{"b":{"c":2,"x":3},"keep":1,"y":4}}`, string(b))
+}
+
+func TestNewGsonFloat(t *testing.T) {
+	js, err := NewGsonFloat([]byte(`{"int":10,"float":5.150,"list":[1]}`))
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, float64(10), js.Get("int").Interface())
+	assert.Equal(t, 5.150, js.Get("float").Interface())
+	assert.Equal(t, float64(1), js.Get("list").GetIndex(0).Interface())
+	assert.Equal(t, 10, js.Get("int").MustInt())
+
+	js, err = NewFromReaderFloat(bytes.NewBufferString(`[2.5]`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2.5, js.GetIndex(0).Interface())
+
+	_, err = NewGsonFloat([]byte(`{`))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestLinked(t *testing.T) {
+	js, err := NewGsonLinked([]byte(`{"id":"root","a":{"b":[{"c":1}]}}`))
+	assert.Equal(t, nil, err)
+
+	c := js.Get("a").Get("b").GetIndex(0).Get("c")
+	assert.Equal(t, 1, c.MustInt())
+	assert.Equal(t, 1, c.Parent().Get("c").MustInt())
+	assert.Equal(t, 1, len(c.Parent().Parent().MustArray()))
+	assert.Equal(t, js, c.Root())
+	assert.Equal(t, "root", c.Root().Get("id").MustString())
+
+	g, ok := js.GetPath("a").CheckGet("b")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, js, g.Parent().Parent())
+
+	e := js.Edit("x", "y")
+	assert.Equal(t, js, e.Root())
+	assert.Equal(t, true, e.Parent().Get("y").MustMap() != nil)
+
+	var nilGson *Gson
+	assert.Equal(t, nilGson, js.Parent())
+
+	// unlinked documents do not track parents
+	plain, _ := NewGson([]byte(`{"a":{"b":1}}`))
+	assert.Equal(t, nilGson, plain.Get("a").Parent())
+	sub := plain.Get("a")
+	assert.Equal(t, sub, sub.Root())
+}
+
+func TestAsString(t *testing.T) {
+	js, err := NewGson([]byte(`{"s":"str","n":1.50,"b":false,"z":null,"a":[1,"x"],"o":{"k":"v"}}`))
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, "str", js.Get("s").AsString())
+	assert.Equal(t, "1.50", js.Get("n").AsString())
+	assert.Equal(t, "false", js.Get("b").AsString())
+	assert.Equal(t, "", js.Get("z").AsString())
+	assert.Equal(t, "", js.Get("missing").AsString())
+	assert.Equal(t, `[1,"x"]`, js.Get("a").AsString())
+	assert.Equal(t, `{"k":"v"}`, js.Get("o").AsString())
+
+	js.Set("i", 42)
+	js.Set("f", 0.25)
+	assert.Equal(t, "42", js.Get("i").AsString())
+	assert.Equal(t, "0.25", js.Get("f").AsString())
+}
+
+func TestAppendPath(t *testing.T) {
+	js, err := NewGson([]byte(`{"result":{"errors":["first"],"count":1}}`))
+	assert.Equal(t, nil, err)
+
+	err = js.AppendPath([]string{"result", "errors"}, "second", "third")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"first", "second", "third"}, js.GetPath("result", "errors").MustStringArray())
+
+	err = js.AppendPath([]string{"result", "warnings", "list"}, 1)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []interface{}{1}, js.GetPath("result", "warnings", "list").MustArray())
+
+	err = js.AppendPath([]string{"result", "count"}, 2)
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, 1, js.GetPath("result", "count").MustInt())
+
+	arr, _ := NewGson([]byte(`[1]`))
+	assert.Equal(t, nil, arr.AppendPath(nil, 2))
+	assert.Equal(t, 2, len(arr.MustArray()))
+
+	empty := &Gson{}
+	assert.Equal(t, nil, empty.AppendPath(nil, "x"))
+	assert.Equal(t, []interface{}{"x"}, empty.MustArray())
+
+	assert.NotEqual(t, nil, js.AppendPath(nil, 1))
+
+	scalar, _ := NewGson([]byte(`"str"`))
+	assert.Equal(t, nil, scalar.AppendPath([]string{"list"}, true))
+	assert.Equal(t, []interface{}{true}, scalar.Get("list").MustArray())
+
+	lazy, _ := NewLazy([]byte(`{"result":{"errors":["first"],"keep":1}}`))
+	assert.Equal(t, nil, lazy.AppendPath([]string{"result", "errors"}, "second"))
+	b, _ := lazy.Encode()
+	assert.Equal(t, `{"result":{"errors":["first","second"],"keep":1}}`, string(b))
+
+	// a failed call leaves the document as it was
+	nested, _ := NewGson([]byte(`{"a":{"b":{"c":"str"}},"keep":1}`))
+	before, _ := nested.Encode()
+	assert.NotEqual(t, nil, nested.AppendPath([]string{"a", "b", "c"}, 1))
+	after, _ := nested.Encode()
+	assert.Equal(t, string(before), string(after))
+	assert.Equal(t, nil, nested.AppendPath([]string{"a", "b", "c", "d"}, 2))
+	assert.Equal(t, []interface{}{2}, nested.GetPath("a", "b", "c", "d").MustArray())
+}
+
+func TestDuration(t *testing.T) {
+	js, err := NewGson([]byte(`{"timeout":"1m30s","nanos":1500,"bad":"soon","flag":true}`))
+	assert.Equal(t, nil, err)
+
+	d, err := js.Get("timeout").Duration()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 90*time.Second, d)
+
+	d, err = js.Get("nanos").Duration()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1500*time.Nanosecond, d)
+
+	_, err = js.Get("bad").Duration()
+	assert.NotEqual(t, nil, err)
+	_, err = js.Get("flag").Duration()
+	assert.NotEqual(t, nil, err)
+
+	assert.Equal(t, 90*time.Second, js.Get("timeout").MustDuration())
+	assert.Equal(t, 5*time.Second, js.Get("bad").MustDuration(5*time.Second))
+	assert.Equal(t, time.Duration(0), js.Get("missing").MustDuration())
+}
+
+func TestUUIDString(t *testing.T) {
+	js, err := NewGson([]byte(`{
+		"id": "123E4567-e89b-12d3-a456-426614174000",
+		"short": "123e4567-e89b-12d3-a456-42661417400",
+		"dashes": "123e4567e-89b-12d3-a456-426614174000",
+		"hex": "123e4567-e89b-12d3-a456-42661417400g",
+		"num": 1
+	}`))
+	assert.Equal(t, nil, err)
+
+	id, err := js.Get("id").UUIDString()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "123e4567-e89b-12d3-a456-426614174000", id)
+
+	for _, key := range []string{"short", "dashes", "hex", "num", "missing"} {
+		_, err = js.Get(key).UUIDString()
+		assert.NotEqual(t, nil, err, key)
+	}
+
+	assert.Equal(t, id, js.Get("id").MustUUIDString())
+	assert.Equal(t, "", js.Get("hex").MustUUIDString())
+	assert.Equal(t, "none", js.Get("num").MustUUIDString("none"))
+}
+
+func TestSetJSON(t *testing.T) {
+	js := New()
+
+	err := js.SetJSON("limits", []byte(`{"max": 10, "tags": ["a"]}`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 10, js.GetPath("limits", "max").MustInt())
+	assert.Equal(t, json.Number("10"), js.GetPath("limits", "max").Interface())
+	assert.Equal(t, []string{"a"}, js.GetPath("limits", "tags").MustStringArray())
+
+	err = js.SetJSONPath([]string{"a", "b"}, []byte(`[1, 2]`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(js.GetPath("a", "b").MustArray()))
+
+	err = js.SetJSON("bad", []byte(`{"max":`))
+	assert.NotEqual(t, nil, err)
+	_, ok := js.CheckGet("bad")
+	assert.Equal(t, false, ok)
+
+	err = js.SetJSONPath([]string{"a", "c"}, []byte(`nope`))
+	assert.NotEqual(t, nil, err)
+
+	err = js.SetJSON("trailing", []byte(`{"a":1} trailing junk`))
+	assert.Equal(t, "invalid JSON", err.Error())
+	_, ok = js.CheckGet("trailing")
+	assert.Equal(t, false, ok)
+	err = js.SetJSONPath([]string{"a", "d"}, []byte(`1 2`))
+	assert.NotEqual(t, nil, err)
+	_, ok = js.Get("a").CheckGet("d")
+	assert.Equal(t, false, ok)
+	assert.Equal(t, nil, js.SetJSON("spaced", []byte(" \n true \n")))
+	assert.Equal(t, true, js.Get("spaced").MustBool())
+}
+
+func TestShallowClone(t *testing.T) {
+	js, err := NewGson([]byte(`{"a":1,"sub":{"b":2}}`))
+	assert.Equal(t, nil, err)
+
+	c := js.ShallowClone()
+	c.Set("extra", true)
+	c.Del("a")
+	assert.Equal(t, 1, js.Get("a").MustInt())
+	_, ok := js.CheckGet("extra")
+	assert.Equal(t, false, ok)
+
+	// nested values are shared
+	c.Get("sub").Set("b", 3)
+	assert.Equal(t, 3, js.GetPath("sub", "b").MustInt())
+
+	arr, _ := NewGson([]byte(`[1,2]`))
+	ac := arr.ShallowClone()
+	ac.MustArray()[0] = "x"
+	assert.Equal(t, 1, arr.GetIndex(0).MustInt())
+
+	s, _ := NewGson([]byte(`"str"`))
+	assert.Equal(t, "str", s.ShallowClone().MustString())
+}
+
+func TestOKAccessors(t *testing.T) {
+	js, err := NewGson([]byte(`{"s":"str","i":10,"f":1.5,"b":true,"a":["x"],"m":{}}`))
+	assert.Equal(t, nil, err)
+
+	s, ok := js.Get("s").StringOK()
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "str", s)
+	_, ok = js.Get("i").StringOK()
+	assert.Equal(t, false, ok)
+
+	i, ok := js.Get("i").IntOK()
+	assert.Equal(t, true, ok)
+	assert.Equal(t, 10, i)
+	_, ok = js.Get("s").IntOK()
+	assert.Equal(t, false, ok)
+
+	i64, ok := js.Get("i").Int64OK()
+	assert.Equal(t, true, ok)
+	assert.Equal(t, int64(10), i64)
+	u64, ok := js.Get("i").Uint64OK()
+	assert.Equal(t, true, ok)
+	assert.Equal(t, uint64(10), u64)
+	f, ok := js.Get("f").Float64OK()
+	assert.Equal(t, true, ok)
+	assert.Equal(t, 1.5, f)
+
+	b, ok := js.Get("b").BoolOK()
+	assert.Equal(t, true, ok)
+	assert.Equal(t, true, b)
+	_, ok = js.Get("missing").BoolOK()
+	assert.Equal(t, false, ok)
+
+	a, ok := js.Get("a").ArrayOK()
+	assert.Equal(t, true, ok)
+	assert.Equal(t, 1, len(a))
+	sa, ok := js.Get("a").StringArrayOK()
+	assert.Equal(t, true, ok)
+	assert.Equal(t, []string{"x"}, sa)
+	_, ok = js.Get("m").MapOK()
+	assert.Equal(t, true, ok)
+	_, ok = js.Get("a").MapOK()
+	assert.Equal(t, false, ok)
+}
+
+func TestOrElseAccessors(t *testing.T) {
+	js, err := NewGson([]byte(`{"s":"str","i":10,"f":1.5,"b":false}`))
+	assert.Equal(t, nil, err)
+
+	calls := 0
+	count := func() { calls++ }
+
+	assert.Equal(t, "str", js.Get("s").StringOrElse(func() string { count(); return "def" }))
+	assert.Equal(t, 10, js.Get("i").IntOrElse(func() int { count(); return -1 }))
+	assert.Equal(t, int64(10), js.Get("i").Int64OrElse(func() int64 { count(); return -1 }))
+	assert.Equal(t, uint64(10), js.Get("i").Uint64OrElse(func() uint64 { count(); return 0 }))
+	assert.Equal(t, 1.5, js.Get("f").Float64OrElse(func() float64 { count(); return 0 }))
+	assert.Equal(t, false, js.Get("b").BoolOrElse(func() bool { count(); return true }))
+	assert.Equal(t, 0, calls)
+
+	assert.Equal(t, "def", js.Get("i").StringOrElse(func() string { count(); return "def" }))
+	assert.Equal(t, -1, js.Get("s").IntOrElse(func() int { count(); return -1 }))
+	assert.Equal(t, int64(-1), js.Get("missing").Int64OrElse(func() int64 { count(); return -1 }))
+	assert.Equal(t, uint64(7), js.Get("s").Uint64OrElse(func() uint64 { count(); return 7 }))
+	assert.Equal(t, 2.5, js.Get("b").Float64OrElse(func() float64 { count(); return 2.5 }))
+	assert.Equal(t, true, js.Get("missing").BoolOrElse(func() bool { count(); return true }))
+	assert.Equal(t, 6, calls)
+}
+
+func TestSetRoot(t *testing.T) {
+	js, err := NewGson([]byte(`{"a":1}`))
+	assert.Equal(t, nil, err)
+
+	js.SetRoot([]interface{}{"x", "y"})
+	assert.Equal(t, []string{"x", "y"}, js.MustStringArray())
+	_, ok := js.CheckGet("a")
+	assert.Equal(t, false, ok)
+
+	js.SetRoot(nil)
+	b, _ := js.Encode()
+	assert.Equal(t, "null", string(b))
+}
+
+func TestWriteTo(t *testing.T) {
+	js, err := NewGson([]byte(`{"a": [1, 2]}`))
+	assert.Equal(t, nil, err)
+
+	var _ io.WriterTo = js
+
+	var buf bytes.Buffer
+	n, err := js.WriteTo(&buf)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(11), n)
+	assert.Equal(t, `{"a":[1,2]}`, buf.String())
+
+	bad := New()
+	bad.Set("f", func() {})
+	_, err = bad.WriteTo(&buf)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestReader(t *testing.T) {
+	js, err := NewGson([]byte(`{"a": [1, 2]}`))
+	assert.Equal(t, nil, err)
+
+	b, err := io.ReadAll(js.Reader())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"a":[1,2]}`, string(b))
+
+	b, err = io.ReadAll(js.PrettyReader())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "{\n  \"a\": [\n    1,\n    2\n  ]\n}", string(b))
+
+	bad := New()
+	bad.Set("f", func() {})
+	_, err = io.ReadAll(bad.Reader())
+	assert.NotEqual(t, nil, err)
+}
+
+func TestSetPathSafe(t *testing.T) {
+	js, err := NewGson([]byte(`{"a":{"b":[1,2],"c":"text","n":null,"d":{}}}`))
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, nil, js.SetPathSafe([]string{"a", "d", "e", "f"}, 1))
+	assert.Equal(t, 1, js.GetPath("a", "d", "e", "f").MustInt())
+	assert.Equal(t, nil, js.SetPathSafe([]string{"a", "b"}, "replaced leaf"))
+	assert.Equal(t, "replaced leaf", js.GetPath("a", "b").MustString())
+
+	err = js.SetPathSafe([]string{"a", "c", "x"}, 1)
+	assert.Equal(t, "/a/c is string, not an object", err.Error())
+	assert.Equal(t, "text", js.GetPath("a", "c").MustString())
+
+	err = js.SetPathSafe([]string{"a", "n", "x"}, 1)
+	assert.Equal(t, "/a/n is null, not an object", err.Error())
+
+	arr, _ := NewGson([]byte(`[1]`))
+	err = arr.SetPathSafe([]string{"x"}, 1)
+	assert.Equal(t, "root is array, not an object", err.Error())
+	assert.Equal(t, []interface{}{json.Number("1")}, arr.MustArray())
+
+	empty := new(Gson)
+	assert.Equal(t, nil, empty.SetPathSafe([]string{"x", "y"}, true))
+	assert.Equal(t, true, empty.GetPath("x", "y").MustBool())
+
+	lazy, err := NewLazy([]byte(`{"a":{"b":{"c":1},"s":"text"}}`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, lazy.SetPathSafe([]string{"a", "b", "d"}, 2))
+	b, _ := lazy.Encode()
+	assert.Equal(t, `{"a":{"b":{"c":1,"d":2},"s":"text"}}`, string(b))
+	err = lazy.SetPathSafe([]string{"a", "s", "x"}, 1)
+	assert.Equal(t, "/a/s is string, not an object", err.Error())
+}
+
+func TestEncodedSize(t *testing.T) {
+	js, err := NewGson([]byte(`{"name":"<ann>","tags":["ü","b"],"n":12345678901234567890,"nested":{"x":null}}`))
+	assert.Equal(t, nil, err)
+	js.Set("score", 1.5)
+
+	b, _ := js.Encode()
+	n, err := js.EncodedSize()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, len(b), n)
+
+	n, _ = New().EncodedSize()
+	assert.Equal(t, 2, n)
+
+	js.Set("bad", func() {})
+	_, err = js.EncodedSize()
+	assert.NotEqual(t, nil, err)
+}
+
+func TestJSONBytes(t *testing.T) {
+	js, err := NewGson([]byte(`{"spec":{"template":{"a":[1,2.5,"x"]},"name":"web"}}`))
+	assert.Equal(t, nil, err)
+
+	b, err := js.GetPath("spec", "template").JSONBytes()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"a":[1,2.5,"x"]}`, string(b))
+
+	b, _ = js.GetPath("spec", "name").JSONBytes()
+	assert.Equal(t, `"web"`, string(b))
+
+	b, _ = js.GetPath("spec", "missing").JSONBytes()
+	assert.Equal(t, `null`, string(b))
+
+	root, _ := js.Encode()
+	b, _ = js.JSONBytes()
+	assert.Equal(t, root, b)
+}
+
+func TestIsEmpty(t *testing.T) {
+	js, err := NewGson([]byte(`{"n":null,"o":{},"a":[],"s":"","zero":0,"f":false,"nested":{"a":null},"aa":[[]],"str":" "}`))
+	assert.Equal(t, nil, err)
+
+	for _, key := range []string{"n", "o", "a", "s", "missing"} {
+		assert.Equal(t, true, js.Get(key).IsEmpty(), key)
+	}
+	for _, key := range []string{"zero", "f", "nested", "aa", "str"} {
+		assert.Equal(t, false, js.Get(key).IsEmpty(), key)
+	}
+	assert.Equal(t, false, js.IsEmpty())
+	assert.Equal(t, true, New().IsEmpty())
+}
+
+func TestNewFromRaw(t *testing.T) {
+	var event struct {
+		Type    string          `json:"type"`
+		Payload json.RawMessage `json:"payload"`
+	}
+	err := json.Unmarshal([]byte(`{"type":"order","payload":{"id":12345678901234567890,"items":[{"sku":"a"}]}}`), &event)
+	assert.Equal(t, nil, err)
+
+	payload, err := NewFromRaw(event.Payload)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, json.Number("12345678901234567890"), payload.Get("id").Interface())
+	assert.Equal(t, "a", payload.Get("items").GetIndex(0).Get("sku").MustString())
+
+	_, err = NewFromRaw(json.RawMessage(`{"broken":`))
+	assert.NotEqual(t, nil, err)
+	_, err = NewFromRaw(nil)
+	assert.NotEqual(t, nil, err)
+}
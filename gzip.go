@@ -0,0 +1,102 @@
+package gson
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+)
+
+// NewFromGzipReader returns a *Gson by decoding gzip-compressed JSON from an
+// io.Reader. Errors in the compressed stream itself, including a truncated
+// stream or a bad checksum, are reported as decompression errors, separate
+// from errors in the JSON it contains, which must be a single value.
+func NewFromGzipReader(r io.Reader) (*Gson, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("decompressing JSON: %w", err)
+	}
+	defer zr.Close()
+
+	src := &gzipSource{r: zr}
+	self := new(Gson)
+	dec := json.NewDecoder(src)
+	dec.UseNumber()
+	err = dec.Decode(&self.data)
+	if err == nil {
+		// the stream must hold exactly one value; reading on to the end also
+		// verifies the checksum
+		if _, terr := dec.Token(); terr != io.EOF {
+			err = errors.New("unexpected data after JSON value")
+		}
+	}
+	if src.err != nil {
+		return nil, fmt.Errorf("decompressing JSON: %w", src.err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return self, nil
+}
+
+// NewFromGzip returns a pointer to a new `Gson` object after decompressing
+// and unmarshaling the gzip-compressed `body` bytes
+func NewFromGzip(body []byte) (*Gson, error) {
+	return NewFromGzipReader(bytes.NewReader(body))
+}
+
+// EncodeGzip returns its marshaled data gzip-compressed. The optional
+// `level` is one of the compress/gzip levels and defaults to
+// gzip.DefaultCompression.
+func (self *Gson) EncodeGzip(level ...int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := self.EncodeGzipTo(&buf, level...); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeGzipTo writes its marshaled data gzip-compressed to `w`, with the
+// same optional `level` as EncodeGzip
+func (self *Gson) EncodeGzipTo(w io.Writer, level ...int) error {
+	lvl := gzip.DefaultCompression
+
+	switch len(level) {
+	case 0:
+	case 1:
+		lvl = level[0]
+	default:
+		log.Panicf("EncodeGzipTo() received too many arguments %d", len(level))
+	}
+
+	b, err := self.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	zw, err := gzip.NewWriterLevel(w, lvl)
+	if err != nil {
+		return err
+	}
+	if _, err := zw.Write(b); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// gzipSource remembers the first error, other than io.EOF, returned by the
+// decompressor so it can be told apart from JSON syntax errors
+type gzipSource struct {
+	r   io.Reader
+	err error
+}
+
+func (s *gzipSource) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if err != nil && err != io.EOF && s.err == nil {
+		s.err = err
+	}
+	return n, err
+}
@@ -0,0 +1,74 @@
+package gson
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"git.egret.io/go/assert"
+	"testing"
+)
+
+func gzipBytes(s string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(s))
+	zw.Close()
+	return buf.Bytes()
+}
+
+func TestNewFromGzip(t *testing.T) {
+	body := gzipBytes(`{"id":12345678901234567890,"tags":["a","b"]}`)
+
+	js, err := NewFromGzip(body)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "12345678901234567890", js.Get("id").AsString())
+	assert.Equal(t, []string{"a", "b"}, js.Get("tags").MustStringArray())
+
+	js, err = NewFromGzipReader(bytes.NewReader(body))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "a", js.Get("tags").GetIndex(0).MustString())
+
+	_, err = NewFromGzip([]byte(`{"plain":true}`))
+	assert.Equal(t, true, errors.Is(err, gzip.ErrHeader))
+
+	corrupt := append([]byte(nil), body...)
+	corrupt[len(corrupt)-8] ^= 0xff
+	_, err = NewFromGzip(corrupt)
+	assert.Equal(t, true, errors.Is(err, gzip.ErrChecksum))
+
+	_, err = NewFromGzip(body[:len(body)-4])
+	assert.Equal(t, "decompressing JSON: unexpected EOF", err.Error())
+
+	_, err = NewFromGzip(gzipBytes(`{"a":`))
+	assert.Equal(t, "unexpected EOF", err.Error())
+
+	for _, raw := range []string{`{"a":1} garbage`, `{"a":1}{"b":2}`, `1 2`} {
+		_, err = NewFromGzip(gzipBytes(raw))
+		assert.Equal(t, "unexpected data after JSON value", err.Error(), raw)
+	}
+	js, err = NewFromGzip(gzipBytes(" {\"a\":1} \n"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, js.Get("a").MustInt())
+}
+
+func TestEncodeGzip(t *testing.T) {
+	js, err := NewGson([]byte(`{"id":12345678901234567890,"text":"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}`))
+	assert.Equal(t, nil, err)
+
+	for _, level := range []int{gzip.DefaultCompression, gzip.NoCompression, gzip.BestCompression} {
+		b, err := js.EncodeGzip(level)
+		assert.Equal(t, nil, err)
+		back, err := NewFromGzip(b)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, true, js.EqualValue(back.Interface()))
+	}
+
+	var buf bytes.Buffer
+	assert.Equal(t, nil, js.EncodeGzipTo(&buf))
+	back, err := NewFromGzipReader(&buf)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "12345678901234567890", back.Get("id").AsString())
+
+	_, err = js.EncodeGzip(42)
+	assert.NotEqual(t, nil, err)
+}
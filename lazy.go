@@ -0,0 +1,100 @@
+package gson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// NewLazy returns a pointer to a new `Gson` object for `body` that defers
+// parsing: only the top level is decoded up front, and each nested object
+// or array is decoded the first time something navigates, walks or writes
+// into it. Subtrees that are never visited are never parsed.
+//
+// until visited, nested values show up in Interface (and inside the
+// containers Map and Array return) as `json.RawMessage`; encoding emits
+// them unchanged. Any `json.RawMessage` found in a document, whether left
+// by NewLazy or set by hand, is treated as such an unparsed subtree.
+func NewLazy(body []byte) (*Gson, error) {
+	if !json.Valid(body) {
+		return nil, errors.New("invalid JSON")
+	}
+	return &Gson{data: parseShallow(body)}, nil
+}
+
+// expandLazy decodes one more level of a value left raw by NewLazy
+func expandLazy(v interface{}) interface{} {
+	if raw, ok := v.(json.RawMessage); ok {
+		return parseShallow(raw)
+	}
+	return v
+}
+
+// expandMember expands, in place, the member `k` of `m` if NewLazy left it
+// raw, and returns it
+func expandMember(m map[string]interface{}, k string) interface{} {
+	v := m[k]
+	if raw, ok := v.(json.RawMessage); ok {
+		v = parseShallow(raw)
+		m[k] = v
+	}
+	return v
+}
+
+// expandElem is the array counterpart of expandMember
+func expandElem(a []interface{}, i int) interface{} {
+	v := a[i]
+	if raw, ok := v.(json.RawMessage); ok {
+		v = parseShallow(raw)
+		a[i] = v
+	}
+	return v
+}
+
+// expandAll decodes, in place, every subtree below `v` left raw by NewLazy
+// and returns the (possibly replaced) root value
+func expandAll(v interface{}) interface{} {
+	return rewriteLeaves(v, func(leaf interface{}) interface{} {
+		return leaf
+	})
+}
+
+// parseShallow decodes the top level of the valid JSON `raw`, keeping
+// nested objects and arrays as `json.RawMessage`
+func parseShallow(raw []byte) interface{} {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil
+	}
+	switch raw[0] {
+	case '{':
+		var members map[string]json.RawMessage
+		json.Unmarshal(raw, &members)
+		m := make(map[string]interface{}, len(members))
+		for k, v := range members {
+			m[k] = shallowLeaf(v)
+		}
+		return m
+	case '[':
+		var elems []json.RawMessage
+		json.Unmarshal(raw, &elems)
+		a := make([]interface{}, len(elems))
+		for i, v := range elems {
+			a[i] = shallowLeaf(v)
+		}
+		return a
+	}
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	dec.Decode(&v)
+	return v
+}
+
+// shallowLeaf decodes scalars right away and keeps containers raw
+func shallowLeaf(raw json.RawMessage) interface{} {
+	if len(raw) > 0 && (raw[0] == '{' || raw[0] == '[') {
+		return raw
+	}
+	return parseShallow(raw)
+}
@@ -0,0 +1,51 @@
+package gson
+
+import (
+	"encoding/json"
+	"git.egret.io/go/assert"
+	"testing"
+)
+
+func TestNewLazy(t *testing.T) {
+	js, err := NewLazy([]byte(` {"a": {"b": [1, {"c": "deep"}]}, "big": {"x": [1, 2, 3]}, "n": 5} `))
+	assert.Equal(t, nil, err)
+
+	// only the top level is decoded
+	m := js.MustMap()
+	_, raw := m["a"].(json.RawMessage)
+	assert.Equal(t, true, raw)
+	assert.Equal(t, json.Number("5"), m["n"])
+
+	assert.Equal(t, "deep", js.Get("a").Get("b").GetIndex(1).Get("c").MustString())
+	assert.Equal(t, 1, js.GetPath("a", "b").GetIndex(0).MustInt())
+
+	// visited subtrees are decoded and cached in place, others stay raw
+	_, raw = m["a"].(json.RawMessage)
+	assert.Equal(t, false, raw)
+	_, raw = m["big"].(json.RawMessage)
+	assert.Equal(t, true, raw)
+
+	big, ok := js.CheckGet("big")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, 3, len(big.Get("x").MustArray()))
+
+	b, err := js.Encode()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"a":{"b":[1,{"c":"deep"}]},"big":{"x":[1,2,3]},"n":5}`, string(b))
+
+	scalar, err := NewLazy([]byte(`"str"`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "str", scalar.MustString())
+
+	_, err = NewLazy([]byte(`{"a":`))
+	assert.NotEqual(t, nil, err)
+
+	// a json.RawMessage set by hand is expanded before it is written through
+	js = New()
+	js.SetPath(nil, json.RawMessage(`{"a":{"b":1}}`))
+	js.SetPath([]string{"a", "c"}, 2)
+	js.Edit("d").Set("e", 3)
+	assert.Equal(t, nil, js.AppendPath([]string{"f"}, 4))
+	b, _ = js.Encode()
+	assert.Equal(t, `{"a":{"b":1,"c":2},"d":{"e":3},"f":[4]}`, string(b))
+}
@@ -0,0 +1,184 @@
+package gson
+
+import (
+	"errors"
+)
+
+// ArrayStrategy selects how MergeWith combines two arrays found at the
+// same position
+type ArrayStrategy int
+
+const (
+	// ArrayReplace replaces the receiver's array with the other one
+	ArrayReplace ArrayStrategy = iota
+	// ArrayConcat appends the other array's elements
+	ArrayConcat
+	// ArrayUnion appends the other array's elements, dropping any element
+	// deep-equal to one already present
+	ArrayUnion
+	// ArrayByKey matches object elements on the value of MergeOptions.Key,
+	// merging matched pairs and appending the rest
+	ArrayByKey
+)
+
+// MergeOptions configures MergeWith
+type MergeOptions struct {
+	Arrays ArrayStrategy
+	// Key names the member identifying elements under ArrayByKey
+	Key string
+}
+
+// MergeWith deep merges `other` into the document in place: objects are
+// merged key by key recursively, arrays are combined as `opts.Arrays`
+// selects, and any other value in `other` replaces the receiver's. Values
+// taken from `other` are copied, so the two documents share nothing after.
+// `other` must be an object; anything else, nil included, is an error.
+//
+//	err := base.MergeWith(overlay, MergeOptions{Arrays: ArrayConcat})
+func (self *Gson) MergeWith(other *Gson, opts MergeOptions) error {
+	if opts.Arrays == ArrayByKey && opts.Key == "" {
+		return errors.New("ArrayByKey merge requires MergeOptions.Key")
+	}
+	if other == nil {
+		return errors.New("MergeWith source is nil")
+	}
+	if _, err := other.Map(); err != nil {
+		return errors.New("MergeWith source is not an object")
+	}
+	self.data = mergeValues(self.data, other.data, opts)
+	return nil
+}
+
+// mergeValues merges `src` into `dst` and returns the result, which the
+// caller stores in place of `dst`. Subtrees left raw by NewLazy are decoded
+// on either side.
+func mergeValues(dst, src interface{}, opts MergeOptions) interface{} {
+	dst = expandLazy(dst)
+	switch s := expandLazy(src).(type) {
+	case map[string]interface{}:
+		d, ok := dst.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for k, v := range s {
+			if cur, ok := d[k]; ok {
+				d[k] = mergeValues(cur, v, opts)
+			} else {
+				d[k] = deepCopy(v)
+			}
+		}
+		return d
+	case []interface{}:
+		d, ok := dst.([]interface{})
+		if !ok {
+			break
+		}
+		return mergeArrays(d, s, opts)
+	}
+	return deepCopy(src)
+}
+
+func mergeArrays(dst, src []interface{}, opts MergeOptions) []interface{} {
+	switch opts.Arrays {
+	case ArrayConcat:
+		return append(dst, deepCopy(src).([]interface{})...)
+	case ArrayUnion:
+		out := make([]interface{}, 0, len(dst)+len(src))
+		for _, v := range dst {
+			if indexOf(out, v) < 0 {
+				out = append(out, v)
+			}
+		}
+		for _, v := range src {
+			if indexOf(out, v) < 0 {
+				out = append(out, deepCopy(v))
+			}
+		}
+		return out
+	case ArrayByKey:
+		for _, v := range src {
+			if i := indexByKey(dst, v, opts.Key); i >= 0 {
+				dst[i] = mergeValues(dst[i], v, opts)
+				continue
+			}
+			dst = append(dst, deepCopy(v))
+		}
+		return dst
+	}
+	return deepCopy(src).([]interface{})
+}
+
+// indexOf returns the index of the first element of `a` deep-equal to `v`
+func indexOf(a []interface{}, v interface{}) int {
+	for i, e := range a {
+		if valuesEqual(e, v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// indexByKey returns the index of the first object in `a` whose `key`
+// member equals that of the object `v`
+func indexByKey(a []interface{}, v interface{}, key string) int {
+	m, ok := expandLazy(v).(map[string]interface{})
+	if !ok {
+		return -1
+	}
+	id, ok := m[key]
+	if !ok {
+		return -1
+	}
+	for i := range a {
+		if em, ok := expandElem(a, i).(map[string]interface{}); ok {
+			if eid, ok := em[key]; ok && valuesEqual(eid, id) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Defaults fills in, recursively and in place, every key of `other` that
+// the document lacks, never overwriting a value it already holds (even a
+// null). Objects present on both sides are descended into; arrays and
+// scalars are only taken from `other` when absent. Both documents must be
+// objects. Values taken from `other` are copied.
+//
+// this is MergeWith with the opposite bias, for layering defaults underneath
+// user settings:
+//
+//	err := userConfig.Defaults(defaultConfig)
+func (self *Gson) Defaults(other *Gson) error {
+	dst, err := self.Map()
+	if err != nil {
+		return errors.New("Defaults target is not an object")
+	}
+	if other == nil {
+		return errors.New("Defaults source is nil")
+	}
+	src, err := other.Map()
+	if err != nil {
+		return errors.New("Defaults source is not an object")
+	}
+	fillDefaults(dst, src)
+	return nil
+}
+
+// fillDefaults copies into `dst` every member of `src` it lacks, descending
+// into objects held by both. Subtrees left raw by NewLazy are decoded.
+func fillDefaults(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if _, ok := dst[k]; !ok {
+			dst[k] = deepCopy(v)
+			continue
+		}
+		d, ok := expandMember(dst, k).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if s, ok := expandLazy(v).(map[string]interface{}); ok {
+			fillDefaults(d, s)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package gson
+
+import (
+	"git.egret.io/go/assert"
+	"testing"
+)
+
+func TestMergeWith(t *testing.T) {
+	base := `{"name":"base","tags":["a","b"],"opts":{"x":1,"list":[1]},"users":[{"id":1,"role":"user"},{"id":2}]}`
+	overlayJSON := `{"name":"overlay","tags":["b","c"],"opts":{"y":2,"list":[2]},"users":[{"id":1,"role":"admin"},{"id":3}],"new":{"k":"v"}}`
+	overlay, _ := NewGson([]byte(overlayJSON))
+
+	cases := []struct {
+		opts MergeOptions
+		want string
+	}{
+		{
+			MergeOptions{},
+			`{"name":"overlay","new":{"k":"v"},"opts":{"list":[2],"x":1,"y":2},"tags":["b","c"],"users":[{"id":1,"role":"admin"},{"id":3}]}`,
+		},
+		{
+			MergeOptions{Arrays: ArrayConcat},
+			`{"name":"overlay","new":{"k":"v"},"opts":{"list":[1,2],"x":1,"y":2},"tags":["a","b","b","c"],"users":[{"id":1,"role":"user"},{"id":2},{"id":1,"role":"admin"},{"id":3}]}`,
+		},
+		{
+			MergeOptions{Arrays: ArrayUnion},
+			`{"name":"overlay","new":{"k":"v"},"opts":{"list":[1,2],"x":1,"y":2},"tags":["a","b","c"],"users":[{"id":1,"role":"user"},{"id":2},{"id":1,"role":"admin"},{"id":3}]}`,
+		},
+		{
+			MergeOptions{Arrays: ArrayByKey, Key: "id"},
+			`{"name":"overlay","new":{"k":"v"},"opts":{"list":[1,2],"x":1,"y":2},"tags":["a","b","b","c"],"users":[{"id":1,"role":"admin"},{"id":2},{"id":3}]}`,
+		},
+	}
+
+	for _, tc := range cases {
+		js, _ := NewGson([]byte(base))
+		assert.Equal(t, nil, js.MergeWith(overlay, tc.opts))
+		b, _ := js.Encode()
+		assert.Equal(t, tc.want, string(b))
+	}
+
+	// subtrees NewLazy left raw merge like parsed ones, on either side
+	lazyOverlay, _ := NewLazy([]byte(overlayJSON))
+	for _, tc := range cases {
+		js, _ := NewLazy([]byte(base))
+		assert.Equal(t, nil, js.MergeWith(lazyOverlay, tc.opts))
+		b, _ := js.Encode()
+		assert.Equal(t, tc.want, string(b))
+	}
+
+	// merged values are copies
+	js, _ := NewGson([]byte(base))
+	js.MergeWith(overlay, MergeOptions{})
+	js.Get("new").Set("k", "changed")
+	assert.Equal(t, "v", overlay.GetPath("new", "k").MustString())
+
+	assert.NotEqual(t, nil, js.MergeWith(overlay, MergeOptions{Arrays: ArrayByKey}))
+
+	assert.Equal(t, "MergeWith source is nil", js.MergeWith(nil, MergeOptions{}).Error())
+	for _, raw := range []string{`[1]`, `"str"`, `null`} {
+		other, _ := NewGson([]byte(raw))
+		assert.Equal(t, "MergeWith source is not an object", js.MergeWith(other, MergeOptions{}).Error())
+	}
+	assert.Equal(t, "overlay", js.Get("name").MustString())
+}
+
+func TestDefaults(t *testing.T) {
+	user, err := NewGson([]byte(`{"name":"mine","server":{"port":9000,"tls":null},"tags":["x"],"mode":"fast"}`))
+	assert.Equal(t, nil, err)
+	defaults, err := NewGson([]byte(`{"name":"default","server":{"port":80,"host":"localhost","tls":{"on":true}},"tags":["a","b"],"mode":{"kind":"slow"},"retries":3,"limits":{"cpu":1}}`))
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, nil, user.Defaults(defaults))
+	b, _ := user.Encode()
+	assert.Equal(t, `{"limits":{"cpu":1},"mode":"fast","name":"mine","retries":3,"server":{"host":"localhost","port":9000,"tls":null},"tags":["x"]}`, string(b))
+
+	lazyUser, _ := NewLazy([]byte(`{"server":{"port":9000,"tls":null}}`))
+	lazyDefaults, _ := NewLazy([]byte(`{"server":{"port":80,"host":"localhost"},"limits":{"cpu":1}}`))
+	assert.Equal(t, nil, lazyUser.Defaults(lazyDefaults))
+	b, _ = lazyUser.Encode()
+	assert.Equal(t, `{"limits":{"cpu":1},"server":{"host":"localhost","port":9000,"tls":null}}`, string(b))
+
+	// values taken from the defaults are copies
+	user.Get("limits").Set("cpu", 4)
+	assert.Equal(t, 1, defaults.GetPath("limits", "cpu").MustInt())
+
+	arr, _ := NewGson([]byte(`[]`))
+	assert.NotEqual(t, nil, user.Defaults(arr))
+	assert.NotEqual(t, nil, arr.Defaults(defaults))
+	assert.Equal(t, "Defaults source is nil", user.Defaults(nil).Error())
+}
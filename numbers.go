@@ -0,0 +1,221 @@
+package gson
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NormalizeNumbers converts, in place, every numeric leaf of the document
+// (json.Number as well as any Go integer or float set programmatically)
+// into its canonical `json.Number` representation.
+//
+// encoding always emits numbers bare, whatever their representation, but
+// after normalizing type switches over Interface() see a single numeric type:
+//
+//	js.Set("count", 3)
+//	js.NormalizeNumbers()
+//	js.Get("count").Interface() // json.Number("3")
+func (self *Gson) NormalizeNumbers() {
+	self.CoerceNumbersTo("json.Number")
+}
+
+// CoerceNumbersTo converts, in place, every numeric leaf of the document
+// (parsed or set programmatically) to the Go representation named by
+// `kind`, and returns the receiver for chaining:
+//
+//   - "json.Number": the canonical `json.Number`, as NormalizeNumbers does
+//   - "float64": float64, except numbers out of float64 range, which stay
+//     as they are
+//   - "int64": int64 for every integral value in int64 range (so 2.0
+//     becomes 2); fractional and out of range numbers stay as they are,
+//     and can be found with CheckNumericPrecision
+//
+// any other `kind` panics.
+//
+//	js.CoerceNumbersTo("float64")
+func (self *Gson) CoerceNumbersTo(kind string) *Gson {
+	var coerce func(n json.Number) (interface{}, bool)
+
+	switch kind {
+	case "json.Number":
+		coerce = func(n json.Number) (interface{}, bool) {
+			return n, true
+		}
+	case "float64":
+		coerce = func(n json.Number) (interface{}, bool) {
+			f, err := n.Float64()
+			return f, err == nil
+		}
+	case "int64":
+		coerce = func(n json.Number) (interface{}, bool) {
+			r, ok := numberRat(n)
+			if !ok || !r.IsInt() || !r.Num().IsInt64() {
+				return nil, false
+			}
+			return r.Num().Int64(), true
+		}
+	default:
+		log.Panicf("CoerceNumbersTo() received unknown kind %q", kind)
+	}
+
+	self.data = rewriteLeaves(self.data, func(v interface{}) interface{} {
+		n, ok := toNumber(v)
+		if !ok {
+			return v
+		}
+		if c, ok := coerce(n); ok {
+			return c
+		}
+		return v
+	})
+	return self
+}
+
+// InterfaceNormalized returns a copy of the underlying data in which every
+// `json.Number` has been converted to a plain Go `int64` (when integral by
+// value and in range, so 1e2 and 2.0 qualify too) or `float64`, so callers
+// can type assert without knowing about json.Number. Interface() remains
+// available for precision-sensitive code.
+func (self *Gson) InterfaceNormalized() interface{} {
+	return copyTree(self.data, func(v interface{}) interface{} {
+		n, ok := v.(json.Number)
+		if !ok {
+			return v
+		}
+		if r, ok := numberRat(n); ok && r.IsInt() && r.Num().IsInt64() {
+			return r.Num().Int64()
+		}
+		if f, err := n.Float64(); err == nil {
+			return f
+		}
+		return v
+	})
+}
+
+// TransformNumbers replaces, in place, every numeric leaf of the document
+// with `fn` applied to its float64 value and returns the receiver for
+// chaining. Results are stored as `json.Number` in their shortest decimal
+// form (so 2.50 becomes 2.5), except NaN and infinities which have no JSON
+// form and are stored as float64.
+//
+//	js.TransformNumbers(func(f float64) float64 {
+//		return math.Round(f*100) / 100
+//	})
+func (self *Gson) TransformNumbers(fn func(f float64) float64) *Gson {
+	self.data = rewriteLeaves(self.data, func(v interface{}) interface{} {
+		if _, ok := toNumber(v); !ok {
+			return v
+		}
+		f, err := (&Gson{data: v}).Float64()
+		if err != nil {
+			return v
+		}
+		f = fn(f)
+		if n, ok := toNumber(f); ok {
+			return n
+		}
+		return f
+	})
+	return self
+}
+
+// InferTypes reinterprets, in place, string leaves that unambiguously hold
+// another JSON type and returns the receiver for chaining. Only exact
+// matches convert:
+//
+//   - "true" and "false" become booleans, and "null" becomes null
+//   - strings following the JSON number grammar become `json.Number`;
+//     anything else stays text, including leading zeros ("007"), a leading
+//     "+" or "." and surrounding whitespace
+//
+// object keys are never changed.
+func (self *Gson) InferTypes() *Gson {
+	self.data = rewriteLeaves(self.data, func(v interface{}) interface{} {
+		s, ok := v.(string)
+		if !ok {
+			return v
+		}
+		switch s {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		}
+		if jsonNumber.MatchString(s) {
+			return json.Number(s)
+		}
+		return v
+	})
+	return self
+}
+
+var jsonNumber = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// CheckNumericPrecision reports every `json.Number` in the document whose
+// value would change if coerced to a fixed-width type: integer literals
+// outside the int64 range, and numbers that float64 can neither hold
+// exactly nor reproduce as the same decimal (e.g. 9007199254740993, or
+// anything out of float64 range). Each number is reported at most once,
+// overflow taking precedence, and each error is prefixed with the number's
+// location as a JSON Pointer.
+func (self *Gson) CheckNumericPrecision() []error {
+	var errs []error
+	walk(nil, self.data, func(path []string, v interface{}) bool {
+		n, ok := v.(json.Number)
+		if !ok {
+			return true
+		}
+		r, ok := new(big.Rat).SetString(n.String())
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s is not a valid number", formatPointer(path), n))
+			return true
+		}
+		if !strings.ContainsAny(n.String(), ".eE") && !r.Num().IsInt64() {
+			errs = append(errs, fmt.Errorf("%s: %s overflows int64", formatPointer(path), n))
+			return true
+		}
+		f, err := n.Float64()
+		if err == nil {
+			// either the exact binary value or the shortest decimal that
+			// round trips must match what was written
+			exact := new(big.Rat).SetFloat64(f)
+			shortest, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+			if exact.Cmp(r) != 0 && shortest.Cmp(r) != 0 {
+				err = fmt.Errorf("%s: %s loses precision as float64", formatPointer(path), n)
+			}
+		} else {
+			err = fmt.Errorf("%s: %s is out of float64 range", formatPointer(path), n)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return true
+	})
+	return errs
+}
+
+// toNumber converts any Go numeric value into a `json.Number` holding
+// exactly the digits encoding/json would emit for it
+func toNumber(v interface{}) (json.Number, bool) {
+	switch v.(type) {
+	case json.Number:
+		return v.(json.Number), true
+	case float32, float64,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+		b, err := json.Marshal(v)
+		if err != nil {
+			// NaN and infinities have no JSON form
+			return "", false
+		}
+		return json.Number(b), true
+	}
+	return "", false
+}
@@ -0,0 +1,195 @@
+package gson
+
+import (
+	"encoding/json"
+	"git.egret.io/go/assert"
+	"math"
+	"testing"
+)
+
+func TestNumbersRoundTrip(t *testing.T) {
+	js, err := NewGson([]byte(`{"parsed":42,"big":18446744073709551615}`))
+	assert.Equal(t, nil, err)
+
+	js.Set("int", 42)
+	js.Set("float", 1.5)
+	js.Set("uint8", uint8(7))
+
+	b, err := js.Encode()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"big":18446744073709551615,"float":1.5,"int":42,"parsed":42,"uint8":7}`, string(b))
+}
+
+func TestNormalizeNumbers(t *testing.T) {
+	js, err := NewGson([]byte(`{"parsed":42,"list":[1.25,"x",null]}`))
+	assert.Equal(t, nil, err)
+
+	js.Set("int", 42)
+	js.Set("float", 1e21)
+	js.Set("nan", math.NaN())
+	js.SetPath([]string{"sub", "n"}, int64(-3))
+	js.Get("list").MustArray()[2] = float32(0.5)
+
+	js.NormalizeNumbers()
+
+	assert.Equal(t, json.Number("42"), js.Get("parsed").Interface())
+	assert.Equal(t, json.Number("42"), js.Get("int").Interface())
+	assert.Equal(t, json.Number("1e+21"), js.Get("float").Interface())
+	assert.Equal(t, json.Number("-3"), js.GetPath("sub", "n").Interface())
+	assert.Equal(t, json.Number("1.25"), js.Get("list").GetIndex(0).Interface())
+	assert.Equal(t, "x", js.Get("list").GetIndex(1).Interface())
+	assert.Equal(t, json.Number("0.5"), js.Get("list").GetIndex(2).Interface())
+
+	// values without a JSON number form are left alone
+	_, ok := js.Get("nan").Interface().(float64)
+	assert.Equal(t, true, ok)
+
+	// subtrees NewLazy left raw are decoded and normalized as well
+	lazy, err := NewLazy([]byte(`{"a":{"b":[1,2.5]}}`))
+	assert.Equal(t, nil, err)
+	lazy.NormalizeNumbers()
+	assert.Equal(t, map[string]interface{}{
+		"a": map[string]interface{}{"b": []interface{}{json.Number("1"), json.Number("2.5")}},
+	}, lazy.Interface())
+}
+
+func TestInterfaceNormalized(t *testing.T) {
+	js, err := NewGson([]byte(`{"int":10,"float":5.5,"exp":1e2,"whole":2.0,"sci":1.5e1,"big":1e19,"arr":[1,{"x":-2}],"s":"str"}`))
+	assert.Equal(t, nil, err)
+
+	v := js.InterfaceNormalized()
+	assert.Equal(t, map[string]interface{}{
+		"int":   int64(10),
+		"float": 5.5,
+		"exp":   int64(100),
+		"whole": int64(2),
+		"sci":   int64(15),
+		"big":   float64(1e19),
+		"arr":   []interface{}{int64(1), map[string]interface{}{"x": int64(-2)}},
+		"s":     "str",
+	}, v)
+
+	// the document itself is unchanged
+	assert.Equal(t, json.Number("10"), js.Get("int").Interface())
+
+	lazy, err := NewLazy([]byte(`{"arr":[1e2,{"x":2.5}]}`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[string]interface{}{
+		"arr": []interface{}{int64(100), map[string]interface{}{"x": 2.5}},
+	}, lazy.InterfaceNormalized())
+}
+
+func TestCheckNumericPrecision(t *testing.T) {
+	js, err := NewGson([]byte(`{
+		"ok": [0.1, 1e300, -42, 9007199254740992, 18446744073709551616.0],
+		"lossy": 9007199254740993,
+		"huge": {"n": 18446744073709551616, "f": 9223372036854775807.0, "both": 18446744073709551617},
+		"range": [1e400],
+		"digits": 0.30000000000000000001
+	}`))
+	assert.Equal(t, nil, err)
+	js.Set("programmatic", uint64(18446744073709551615))
+
+	var msgs []string
+	for _, err := range js.CheckNumericPrecision() {
+		msgs = append(msgs, err.Error())
+	}
+	assert.Equal(t, []string{
+		"/digits: 0.30000000000000000001 loses precision as float64",
+		"/huge/both: 18446744073709551617 overflows int64",
+		"/huge/f: 9223372036854775807.0 loses precision as float64",
+		"/huge/n: 18446744073709551616 overflows int64",
+		"/lossy: 9007199254740993 loses precision as float64",
+		"/range/0: 1e400 is out of float64 range",
+	}, msgs)
+
+	clean, _ := NewGson([]byte(`[1, 2.5, "9007199254740993"]`))
+	assert.Equal(t, 0, len(clean.CheckNumericPrecision()))
+}
+
+func TestTransformNumbers(t *testing.T) {
+	js, err := NewGson([]byte(`{"a":1.234,"b":[2.50,"3"],"c":{"d":-0.005}}`))
+	assert.Equal(t, nil, err)
+	js.Set("e", 10)
+
+	ret := js.TransformNumbers(func(f float64) float64 {
+		return math.Round(f*100) / 100
+	})
+	assert.Equal(t, js, ret)
+
+	b, _ := js.Encode()
+	assert.Equal(t, `{"a":1.23,"b":[2.5,"3"],"c":{"d":-0.01},"e":10}`, string(b))
+	assert.Equal(t, json.Number("10"), js.Get("e").Interface())
+
+	js.TransformNumbers(func(f float64) float64 { return f / 0 })
+	_, ok := js.Get("a").Interface().(float64)
+	assert.Equal(t, true, ok)
+
+	lazy, err := NewLazy([]byte(`{"c":{"d":[-0.005,1.234]}}`))
+	assert.Equal(t, nil, err)
+	lazy.TransformNumbers(func(f float64) float64 { return math.Round(f*100) / 100 })
+	b, _ = lazy.Encode()
+	assert.Equal(t, `{"c":{"d":[-0.01,1.23]}}`, string(b))
+}
+
+func TestInferTypes(t *testing.T) {
+	js, err := NewGson([]byte(`{"n":"42","f":"-1.5e3","t":"true","F":"false","z":"null",
+		"zip":"007","plus":"+1","space":" 1","text":"hello","True":"True","dot":".5",
+		"rows":[["1","x"]],"real":3,"123":"key"}`))
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, js, js.InferTypes())
+
+	b, _ := js.Encode()
+	assert.Equal(t, `{"123":"key","F":false,"True":"True","dot":".5","f":-1.5e3,"n":42,"plus":"+1",`+
+		`"real":3,"rows":[[1,"x"]],"space":" 1","t":true,"text":"hello","z":null,"zip":"007"}`, string(b))
+	assert.Equal(t, json.Number("42"), js.Get("n").Interface())
+
+	lazy, err := NewLazy([]byte(`{"rows":[["1","x"],{"ok":"true"}]}`))
+	assert.Equal(t, nil, err)
+	b, _ = lazy.InferTypes().Encode()
+	assert.Equal(t, `{"rows":[[1,"x"],{"ok":true}]}`, string(b))
+}
+
+func TestCoerceNumbersTo(t *testing.T) {
+	body := []byte(`{"i":3,"f":2.5,"whole":2.0,"big":18446744073709551616,"huge":1e400,"list":[1,"1",null]}`)
+	setup := func() *Gson {
+		js, err := NewGson(body)
+		assert.Equal(t, nil, err)
+		js.Set("go_int", 7)
+		js.Set("go_float", float32(0.5))
+		return js
+	}
+
+	js := setup().CoerceNumbersTo("float64")
+	assert.Equal(t, 3.0, js.Get("i").Interface())
+	assert.Equal(t, 2.5, js.Get("f").Interface())
+	assert.Equal(t, 7.0, js.Get("go_int").Interface())
+	assert.Equal(t, 0.5, js.Get("go_float").Interface())
+	assert.Equal(t, 1.8446744073709552e19, js.Get("big").Interface())
+	assert.Equal(t, json.Number("1e400"), js.Get("huge").Interface())
+	assert.Equal(t, []interface{}{1.0, "1", nil}, js.Get("list").Interface())
+
+	js = setup().CoerceNumbersTo("int64")
+	assert.Equal(t, int64(3), js.Get("i").Interface())
+	assert.Equal(t, int64(2), js.Get("whole").Interface())
+	assert.Equal(t, int64(7), js.Get("go_int").Interface())
+	assert.Equal(t, json.Number("2.5"), js.Get("f").Interface())
+	assert.Equal(t, float32(0.5), js.Get("go_float").Interface())
+	assert.Equal(t, json.Number("18446744073709551616"), js.Get("big").Interface())
+
+	js = setup().CoerceNumbersTo("json.Number")
+	assert.Equal(t, json.Number("7"), js.Get("go_int").Interface())
+	assert.Equal(t, json.Number("0.5"), js.Get("go_float").Interface())
+	assert.Equal(t, json.Number("2.0"), js.Get("whole").Interface())
+
+	lazy, err := NewLazy([]byte(`{"sub":{"n":[3,2.5]}}`))
+	assert.Equal(t, nil, err)
+	lazy.CoerceNumbersTo("float64")
+	assert.Equal(t, []interface{}{3.0, 2.5}, lazy.GetPath("sub", "n").Interface())
+
+	defer func() {
+		assert.NotEqual(t, nil, recover())
+	}()
+	setup().CoerceNumbersTo("int32")
+}
@@ -0,0 +1,351 @@
+package gson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Select returns a pointer to a new `Gson` object built by reading each
+// source path of `mapping` (as GetPath would) and writing the value under
+// the corresponding output key. Missing sources are written as null unless
+// `skipMissing` is true, in which case the output key is left out.
+//
+//	js.Select(map[string][]string{
+//		"name": {"user", "profile", "name"},
+//		"city": {"user", "address", "city"},
+//	})
+func (self *Gson) Select(mapping map[string][]string, skipMissing ...bool) (*Gson, error) {
+	var skip bool
+
+	switch len(skipMissing) {
+	case 0:
+	case 1:
+		skip = skipMissing[0]
+	default:
+		log.Panicf("Select() received too many arguments %d", len(skipMissing))
+	}
+
+	if _, err := self.Map(); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]interface{}, len(mapping))
+	for key, branch := range mapping {
+		val, ok := self.lookup(branch)
+		if !ok && skip {
+			continue
+		}
+		out[key] = val
+	}
+	return &Gson{data: out}, nil
+}
+
+// lookup returns the value at `branch` and whether every key along it exists
+func (self *Gson) lookup(branch []string) (interface{}, bool) {
+	curr := self.data
+	for _, b := range branch {
+		m, ok := curr.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if curr, ok = m[b]; !ok {
+			return nil, false
+		}
+		curr = expandMember(m, b)
+	}
+	return curr, true
+}
+
+// MapValues returns a pointer to a new `Gson` object holding, for every
+// entry of its `map`, the result of calling `fn` with the key and the
+// wrapped value. The original object is left untouched.
+//
+//	strs, err := js.Get("config").MapValues(func(k string, v *Gson) interface{} {
+//		return v.AsString()
+//	})
+func (self *Gson) MapValues(fn func(key string, value *Gson) interface{}) (*Gson, error) {
+	m, err := self.Map()
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = fn(k, self.child(v))
+	}
+	return &Gson{data: out}, nil
+}
+
+// Wrap returns a pointer to a new `Gson` object holding an object with the
+// single member `key` set to its data, which is shared rather than copied
+//
+// useful for building response envelopes:
+//
+//	resp := result.Wrap("data")
+//	resp.Set("version", 2)
+func (self *Gson) Wrap(key string) *Gson {
+	return &Gson{data: map[string]interface{}{key: self.data}}
+}
+
+// Unwrap is the inverse of Wrap: it returns the value under `key` if the
+// node is an object holding that key, and otherwise a `Gson` object
+// wrapping nil, or the node itself when `orSelf` is true
+//
+// useful for peeling off optional envelopes:
+//
+//	payload := resp.Unwrap("data", true)
+func (self *Gson) Unwrap(key string, orSelf ...bool) *Gson {
+	var fallback bool
+
+	switch len(orSelf) {
+	case 0:
+	case 1:
+		fallback = orSelf[0]
+	default:
+		log.Panicf("Unwrap() received too many arguments %d", len(orSelf))
+	}
+
+	if v, ok := self.CheckGet(key); ok {
+		return v
+	}
+	if fallback {
+		return self
+	}
+	return self.child(nil)
+}
+
+// GetKeyAt returns the key and value of the member at position `index` of
+// its `map` representation. Positions follow Encode's output: insertion
+// order on documents created by NewOrdered, sorted key order otherwise. A
+// negative `index` counts back from the end, so -1 is the last key.
+func (self *Gson) GetKeyAt(index int) (string, *Gson, error) {
+	m, err := self.Map()
+	if err != nil {
+		return "", nil, err
+	}
+	var keys []string
+	if self.order != nil {
+		keys = self.order.keys(m)
+	} else {
+		keys = sortedKeys(m)
+	}
+	i := index
+	if i < 0 {
+		i += len(keys)
+	}
+	if i < 0 || i >= len(keys) {
+		return "", nil, fmt.Errorf("key index %d out of range (%d keys)", index, len(keys))
+	}
+	v, _ := self.CheckGet(keys[i])
+	return keys[i], v, nil
+}
+
+// ShardByKeys distributes the members of its `map` representation across
+// `n` new objects, dealing them out round-robin in sorted key order so the
+// shards differ in size by at most one member. Exactly `n` shards are
+// returned, some empty when there are fewer than `n` keys. Member values
+// are shared with the document, not copied; since every key lands in a
+// single shard, shards can be processed concurrently as long as the
+// document itself is left alone.
+//
+//	shards, err := js.ShardByKeys(runtime.NumCPU())
+func (self *Gson) ShardByKeys(n int) ([]*Gson, error) {
+	m, err := self.Map()
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("shard count must be positive, got %d", n)
+	}
+	shards := make([]*Gson, n)
+	for i := range shards {
+		shards[i] = &Gson{data: make(map[string]interface{}, len(m)/n+1)}
+	}
+	for i, k := range sortedKeys(m) {
+		shards[i%n].data.(map[string]interface{})[k] = m[k]
+	}
+	return shards, nil
+}
+
+// GetGlob returns the members of its `map` representation whose keys
+// match the glob `pattern`, with path.Match semantics, each wrapped in a
+// `Gson` object. A node that isn't an object, or a malformed pattern,
+// yields no members.
+//
+//	for key, user := range js.GetGlob("user_*") {
+//		fmt.Println(key, user.Get("name").MustString())
+//	}
+func (self *Gson) GetGlob(pattern string) map[string]*Gson {
+	found := make(map[string]*Gson)
+	m, err := self.Map()
+	if err != nil {
+		return found
+	}
+	for k := range m {
+		if ok, _ := path.Match(pattern, k); ok {
+			found[k], _ = self.CheckGet(k)
+		}
+	}
+	return found
+}
+
+// MissingKeysError is returned by RequireKeys and lists every required key
+// that was absent or null, in the order they were requested.
+type MissingKeysError struct {
+	Keys []string
+}
+
+func (self *MissingKeysError) Error() string {
+	return fmt.Sprintf("missing required keys: %s", strings.Join(self.Keys, ", "))
+}
+
+// RequireKeys checks that each of `keys` is present in its `map`
+// representation with a non-null value. All offending keys are reported
+// at once through a *MissingKeysError, so callers can surface complete
+// validation feedback:
+//
+//	if err := js.RequireKeys("id", "name", "email"); err != nil {
+//		var missing *gson.MissingKeysError
+//		if errors.As(err, &missing) {
+//			fmt.Println(missing.Keys)
+//		}
+//	}
+func (self *Gson) RequireKeys(keys ...string) error {
+	m, err := self.Map()
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, k := range keys {
+		if v, ok := m[k]; !ok || v == nil {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) > 0 {
+		return &MissingKeysError{Keys: missing}
+	}
+	return nil
+}
+
+// EncodeOnly returns the marshaled form of a shallow projection of its
+// `map` representation holding just the named top-level `keys`. Keys that
+// aren't present are left out and the document itself isn't modified.
+//
+//	b, err := js.EncodeOnly("id", "name")
+func (self *Gson) EncodeOnly(keys ...string) ([]byte, error) {
+	m, err := self.Map()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]interface{}, len(keys))
+	for _, k := range keys {
+		if v, ok := m[k]; ok {
+			out[k] = v
+		}
+	}
+	return json.Marshal(out)
+}
+
+// EncodeExcept returns the marshaled form of its `map` representation
+// without the named top-level `keys`, e.g. to strip internal fields
+// before a document leaves the process. The document itself isn't
+// modified.
+//
+//	b, err := js.EncodeExcept("password", "internal_notes")
+func (self *Gson) EncodeExcept(keys ...string) ([]byte, error) {
+	m, err := self.Map()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	for _, k := range keys {
+		delete(out, k)
+	}
+	return json.Marshal(out)
+}
+
+// EncodeExceptPaths is like EncodeExcept but takes JSON Pointers (RFC
+// 6901), so nested members and array elements can be left out too. All
+// pointers are resolved against the original document, and those that
+// lead nowhere are ignored. Only the containers along the excluded paths
+// are copied; the document itself isn't modified.
+//
+//	b, err := js.EncodeExceptPaths("/password", "/tokens/0", "/profile/ssn")
+func (self *Gson) EncodeExceptPaths(paths ...string) ([]byte, error) {
+	root := &exclusion{}
+	for _, p := range paths {
+		tokens, err := parsePointer(p)
+		if err != nil {
+			return nil, err
+		}
+		if len(tokens) == 0 {
+			return nil, errors.New("cannot exclude the root")
+		}
+		root.add(tokens)
+	}
+	return json.Marshal(root.omit(self.data))
+}
+
+// exclusion is a trie of the JSON Pointer tokens EncodeExceptPaths leaves out
+type exclusion struct {
+	all  bool
+	next map[string]*exclusion
+}
+
+func (self *exclusion) add(tokens []string) {
+	for _, t := range tokens {
+		if self.next == nil {
+			self.next = make(map[string]*exclusion)
+		}
+		e, ok := self.next[t]
+		if !ok {
+			e = &exclusion{}
+			self.next[t] = e
+		}
+		self = e
+	}
+	self.all = true
+}
+
+// omit returns `v` without the excluded members, copying only the
+// containers that actually lose something below them. Subtrees left raw
+// by NewLazy are decoded where an excluded path runs through them.
+func (self *exclusion) omit(v interface{}) interface{} {
+	switch c := expandLazy(v).(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(c))
+		for k, e := range c {
+			if ex, ok := self.next[k]; ok {
+				if ex.all {
+					continue
+				}
+				e = ex.omit(e)
+			}
+			out[k] = e
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, 0, len(c))
+		for i, e := range c {
+			if ex, ok := self.next[strconv.Itoa(i)]; ok {
+				if ex.all {
+					continue
+				}
+				e = ex.omit(e)
+			}
+			out = append(out, e)
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,291 @@
+package gson
+
+import (
+	"errors"
+	"git.egret.io/go/assert"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	js, err := NewGson([]byte(`{"user":{"profile":{"name":"ann"},"tags":["a"],"nick":null}}`))
+	assert.Equal(t, nil, err)
+
+	mapping := map[string][]string{
+		"name":    {"user", "profile", "name"},
+		"tags":    {"user", "tags"},
+		"nick":    {"user", "nick"},
+		"missing": {"user", "profile", "age"},
+	}
+
+	sel, err := js.Select(mapping)
+	assert.Equal(t, nil, err)
+	b, _ := sel.Encode()
+	assert.Equal(t, `{"missing":null,"name":"ann","nick":null,"tags":["a"]}`, string(b))
+
+	sel, err = js.Select(mapping, true)
+	assert.Equal(t, nil, err)
+	b, _ = sel.Encode()
+	assert.Equal(t, `{"name":"ann","nick":null,"tags":["a"]}`, string(b))
+
+	_, err = js.Get("user").Get("tags").Select(mapping)
+	assert.NotEqual(t, nil, err)
+
+	lazy, _ := NewLazy([]byte(`{"user":{"profile":{"name":"ann","tags":["a"]}}}`))
+	sel, err = lazy.Select(map[string][]string{
+		"name":    {"user", "profile", "name"},
+		"profile": {"user", "profile"},
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "ann", sel.Get("name").MustString())
+	assert.Equal(t, "a", sel.GetPath("profile", "tags").GetIndex(0).MustString())
+}
+
+func TestMapValues(t *testing.T) {
+	js, err := NewGson([]byte(`{"a":1,"b":true,"c":"x"}`))
+	assert.Equal(t, nil, err)
+
+	strs, err := js.MapValues(func(k string, v *Gson) interface{} {
+		return k + "=" + v.AsString()
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[string]interface{}{"a": "a=1", "b": "b=true", "c": "c=x"}, strs.MustMap())
+	assert.Equal(t, 1, js.Get("a").MustInt())
+
+	_, err = js.Get("a").MapValues(func(string, *Gson) interface{} { return nil })
+	assert.NotEqual(t, nil, err)
+
+	lazy, _ := NewLazy([]byte(`{"a":{"n":1},"b":[2]}`))
+	kinds, err := lazy.MapValues(func(k string, v *Gson) interface{} {
+		return kindOf(v.Interface())
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[string]interface{}{"a": "object", "b": "array"}, kinds.MustMap())
+}
+
+func TestWrap(t *testing.T) {
+	js, err := NewGson([]byte(`[1,2]`))
+	assert.Equal(t, nil, err)
+
+	resp := js.Wrap("data")
+	resp.Set("version", 2)
+	b, _ := resp.Encode()
+	assert.Equal(t, `{"data":[1,2],"version":2}`, string(b))
+
+	b, _ = resp.Wrap("outer").Encode()
+	assert.Equal(t, `{"outer":{"data":[1,2],"version":2}}`, string(b))
+
+	b, _ = (&Gson{}).Wrap("x").Encode()
+	assert.Equal(t, `{"x":null}`, string(b))
+}
+
+func TestUnwrap(t *testing.T) {
+	js, err := NewGson([]byte(`{"data":{"id":7},"meta":{}}`))
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, 7, js.Unwrap("data").Get("id").MustInt())
+	assert.Equal(t, nil, js.Unwrap("missing").Interface())
+	assert.Equal(t, js, js.Unwrap("missing", true))
+
+	// the unwrapped value is shared with the envelope
+	js.Unwrap("data").Set("seen", true)
+	assert.Equal(t, true, js.GetPath("data", "seen").MustBool())
+
+	arr, _ := NewGson([]byte(`[1]`))
+	assert.Equal(t, nil, arr.Unwrap("data").Interface())
+	assert.Equal(t, arr, arr.Unwrap("data", true))
+
+	roundTrip := arr.Wrap("data").Unwrap("data")
+	assert.Equal(t, arr.Interface(), roundTrip.Interface())
+}
+
+func TestGetKeyAt(t *testing.T) {
+	js, err := NewGson([]byte(`{"b":{"x":1},"c":3,"a":"first"}`))
+	assert.Equal(t, nil, err)
+
+	k, v, err := js.GetKeyAt(0)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "a", k)
+	assert.Equal(t, "first", v.MustString())
+
+	k, v, _ = js.GetKeyAt(1)
+	assert.Equal(t, "b", k)
+	assert.Equal(t, 1, v.Get("x").MustInt())
+
+	k, v, _ = js.GetKeyAt(-1)
+	assert.Equal(t, "c", k)
+	assert.Equal(t, 3, v.MustInt())
+
+	_, _, err = js.GetKeyAt(3)
+	assert.Equal(t, "key index 3 out of range (3 keys)", err.Error())
+	_, _, err = js.GetKeyAt(-4)
+	assert.NotEqual(t, nil, err)
+
+	arr, _ := NewGson([]byte(`[1]`))
+	_, _, err = arr.GetKeyAt(0)
+	assert.NotEqual(t, nil, err)
+
+	// ordered documents use insertion order
+	ordered, err := NewOrdered([]byte(`{"b":{"x":1,"a":2},"c":3,"a":"first"}`))
+	assert.Equal(t, nil, err)
+	k, v, _ = ordered.GetKeyAt(0)
+	assert.Equal(t, "b", k)
+	k, _, _ = v.GetKeyAt(-1)
+	assert.Equal(t, "a", k)
+	ordered.Set("d", 4)
+	k, v, _ = ordered.GetKeyAt(-1)
+	assert.Equal(t, "d", k)
+	assert.Equal(t, 4, v.MustInt())
+	k, _, _ = ordered.GetKeyAt(2)
+	assert.Equal(t, "a", k)
+}
+
+func TestShardByKeys(t *testing.T) {
+	js, err := NewGson([]byte(`{"e":5,"a":1,"d":4,"b":2,"c":3}`))
+	assert.Equal(t, nil, err)
+
+	shards, err := js.ShardByKeys(2)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(shards))
+	b0, _ := shards[0].Encode()
+	b1, _ := shards[1].Encode()
+	assert.Equal(t, `{"a":1,"c":3,"e":5}`, string(b0))
+	assert.Equal(t, `{"b":2,"d":4}`, string(b1))
+
+	shards, _ = js.ShardByKeys(7)
+	assert.Equal(t, 7, len(shards))
+	total := 0
+	for _, s := range shards {
+		total += len(s.MustMap())
+	}
+	assert.Equal(t, 5, total)
+	assert.Equal(t, 0, len(shards[6].MustMap()))
+
+	_, err = js.ShardByKeys(0)
+	assert.Equal(t, "shard count must be positive, got 0", err.Error())
+
+	arr, _ := NewGson([]byte(`[1,2]`))
+	_, err = arr.ShardByKeys(2)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestGetGlob(t *testing.T) {
+	js, err := NewGson([]byte(`{"user_1":{"name":"ann"},"user_2":{"name":"bob"},"admin_1":{},"user":"plain","users/x":1}`))
+	assert.Equal(t, nil, err)
+
+	found := js.GetGlob("user_*")
+	assert.Equal(t, 2, len(found))
+	assert.Equal(t, "ann", found["user_1"].Get("name").MustString())
+	assert.Equal(t, "bob", found["user_2"].Get("name").MustString())
+
+	assert.Equal(t, 3, len(js.GetGlob("*_?")))
+	assert.Equal(t, 1, len(js.GetGlob("user")))
+	assert.Equal(t, 0, len(js.GetGlob("users*")))
+	assert.Equal(t, 1, len(js.GetGlob("users/*")))
+	assert.Equal(t, 2, len(js.GetGlob("user_[12]")))
+	assert.Equal(t, 0, len(js.GetGlob("user_[")))
+
+	// the values are live views into the document
+	found["user_1"].Set("seen", true)
+	assert.Equal(t, true, js.GetPath("user_1", "seen").MustBool())
+
+	arr, _ := NewGson([]byte(`[1]`))
+	assert.Equal(t, 0, len(arr.GetGlob("*")))
+}
+
+func TestRequireKeys(t *testing.T) {
+	js, err := NewGson([]byte(`{"id":1,"name":"ann","email":null,"tags":[]}`))
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, nil, js.RequireKeys())
+	assert.Equal(t, nil, js.RequireKeys("id", "name", "tags"))
+
+	err = js.RequireKeys("id", "email", "phone", "name")
+	var missing *MissingKeysError
+	assert.Equal(t, true, errors.As(err, &missing))
+	assert.Equal(t, []string{"email", "phone"}, missing.Keys)
+	assert.Equal(t, "missing required keys: email, phone", err.Error())
+
+	arr, _ := NewGson([]byte(`[1]`))
+	err = arr.RequireKeys("id")
+	assert.Equal(t, false, errors.As(err, &missing))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestEncodeOnly(t *testing.T) {
+	js, err := NewGson([]byte(`{"id":1,"name":"ann","password":"x","tags":["a"],"n":null}`))
+	assert.Equal(t, nil, err)
+
+	b, err := js.EncodeOnly("name", "id", "missing", "n")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"id":1,"n":null,"name":"ann"}`, string(b))
+
+	b, err = js.EncodeOnly()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{}`, string(b))
+
+	// the document itself is left alone
+	assert.Equal(t, 5, len(js.MustMap()))
+
+	lazy, _ := NewLazy([]byte(`{"id":1,"profile":{"age":3,"tags":["a"]},"secret":{"k":"v"}}`))
+	b, err = lazy.EncodeOnly("profile", "id")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"id":1,"profile":{"age":3,"tags":["a"]}}`, string(b))
+
+	arr, _ := NewGson([]byte(`[1]`))
+	_, err = arr.EncodeOnly("id")
+	assert.NotEqual(t, nil, err)
+}
+
+func TestEncodeExcept(t *testing.T) {
+	js, err := NewGson([]byte(`{"id":1,"name":"ann","password":"x","tags":["a"]}`))
+	assert.Equal(t, nil, err)
+
+	b, err := js.EncodeExcept("password", "tags", "missing")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"id":1,"name":"ann"}`, string(b))
+	assert.Equal(t, "x", js.Get("password").MustString())
+
+	lazy, _ := NewLazy([]byte(`{"id":1,"profile":{"age":3,"tags":["a"]},"secret":{"k":"v"}}`))
+	b, err = lazy.EncodeExcept("secret")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"id":1,"profile":{"age":3,"tags":["a"]}}`, string(b))
+
+	arr, _ := NewGson([]byte(`[1]`))
+	_, err = arr.EncodeExcept("id")
+	assert.NotEqual(t, nil, err)
+}
+
+func TestEncodeExceptPaths(t *testing.T) {
+	raw := `{"user":{"name":"ann","ssn":"123","a/b":1},"tokens":["t0","t1","t2"],"password":"x","n":5}`
+	js, err := NewGson([]byte(raw))
+	assert.Equal(t, nil, err)
+
+	b, err := js.EncodeExceptPaths("/password", "/user/ssn", "/user/a~1b", "/tokens/0", "/tokens/2", "/missing/x", "/n/deeper", "/tokens/01")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"n":5,"tokens":["t1"],"user":{"name":"ann"}}`, string(b))
+
+	lazy, err := NewLazy([]byte(raw))
+	assert.Equal(t, nil, err)
+	b, err = lazy.EncodeExceptPaths("/password", "/user/ssn", "/user/a~1b", "/tokens/0", "/tokens/2")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"n":5,"tokens":["t1"],"user":{"name":"ann"}}`, string(b))
+
+	// a shallower exclusion wins over a deeper one
+	b, err = js.EncodeExceptPaths("/user/name", "/user")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"n":5,"password":"x","tokens":["t0","t1","t2"]}`, string(b))
+
+	b, err = js.EncodeExceptPaths()
+	assert.Equal(t, nil, err)
+	orig, _ := js.Encode()
+	assert.Equal(t, string(orig), string(b))
+
+	// the document itself is left alone
+	fresh, _ := NewGson([]byte(raw))
+	assert.Equal(t, true, js.EqualIgnoring(fresh, nil))
+
+	_, err = js.EncodeExceptPaths("password")
+	assert.NotEqual(t, nil, err)
+	_, err = js.EncodeExceptPaths("")
+	assert.Equal(t, "cannot exclude the root", err.Error())
+}
@@ -0,0 +1,166 @@
+package gson
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// NewOrdered is like NewGson but remembers the order in which the members
+// of every object appear in `body`, and Encode, EncodePretty and
+// MarshalJSON emit them in that order instead of sorted. The order carries
+// over to every `Gson` object navigated to from the result.
+//
+// Set on an existing key updates the value in place, keeping its position,
+// and appends keys that are new; Del forgets a key's position, so setting
+// it again moves it to the end. Members added by other means, such as
+// SetPath or writing to the map Map returns, follow the recorded ones in
+// sorted order, as do all members of objects created after parsing.
+// Copies made by other methods encode sorted as usual.
+//
+// useful for minimal diffs when tweaking a field of a config and
+// writing it back:
+//
+//	js, err := gson.NewOrdered(body)
+//	js.Get("server").Set("port", 8081)
+//	out, err := js.EncodePretty()
+func NewOrdered(body []byte) (*Gson, error) {
+	b := &treeBuilder{order: new(keyOrder)}
+	if err := Parse(bytes.NewReader(body), b); err != nil {
+		return nil, err
+	}
+	return &Gson{data: b.root, order: b.order}, nil
+}
+
+// keyOrder records the member order of the objects of a document created
+// by NewOrdered. Objects are told apart by the identity of their map; each
+// entry holds on to its map so that the address can't be reused by another.
+type keyOrder struct {
+	objects map[uintptr]*orderedKeys
+}
+
+type orderedKeys struct {
+	m    map[string]interface{}
+	keys []string
+}
+
+func (self *keyOrder) entry(m map[string]interface{}, create bool) *orderedKeys {
+	id := reflect.ValueOf(m).Pointer()
+	e, ok := self.objects[id]
+	if !ok && create {
+		if self.objects == nil {
+			self.objects = make(map[uintptr]*orderedKeys)
+		}
+		e = &orderedKeys{m: m}
+		self.objects[id] = e
+	}
+	return e
+}
+
+// add appends `key` to the order of `m` unless it is already listed
+func (self *keyOrder) add(m map[string]interface{}, key string) {
+	e := self.entry(m, true)
+	for _, k := range e.keys {
+		if k == key {
+			return
+		}
+	}
+	e.keys = append(e.keys, key)
+}
+
+// remove drops `key` from the order of `m`
+func (self *keyOrder) remove(m map[string]interface{}, key string) {
+	e := self.entry(m, false)
+	if e == nil {
+		return
+	}
+	for i, k := range e.keys {
+		if k == key {
+			e.keys = append(e.keys[:i], e.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+// keys returns the keys of `m` in recorded order, followed by any others
+// sorted
+func (self *keyOrder) keys(m map[string]interface{}) []string {
+	e := self.entry(m, false)
+	if e == nil {
+		return sortedKeys(m)
+	}
+	keys := make([]string, 0, len(m))
+	listed := make(map[string]bool, len(e.keys))
+	for _, k := range e.keys {
+		if _, ok := m[k]; ok {
+			keys = append(keys, k)
+			listed[k] = true
+		}
+	}
+	var rest []string
+	for k := range m {
+		if !listed[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+// marshal encodes `v` like json.Marshal, but with the members of objects in
+// recorded order
+func (self *keyOrder) marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := self.encode(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (self *keyOrder) encode(buf *bytes.Buffer, v interface{}) error {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		if c == nil {
+			break
+		}
+		buf.WriteByte('{')
+		for i, k := range self.keys(c) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			b, err := json.Marshal(k)
+			if err != nil {
+				return err
+			}
+			buf.Write(b)
+			buf.WriteByte(':')
+			if err := self.encode(buf, c[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case []interface{}:
+		if c == nil {
+			break
+		}
+		buf.WriteByte('[')
+		for i, e := range c {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := self.encode(buf, e); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
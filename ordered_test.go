@@ -0,0 +1,46 @@
+package gson
+
+import (
+	"git.egret.io/go/assert"
+	"testing"
+)
+
+func TestNewOrdered(t *testing.T) {
+	js, err := NewOrdered([]byte(`{"name":"svc","server":{"port":80,"host":"a"},"tags":["x",{"z":1,"y":2}],"debug":false}`))
+	assert.Equal(t, nil, err)
+
+	b, _ := js.Encode()
+	assert.Equal(t, `{"name":"svc","server":{"port":80,"host":"a"},"tags":["x",{"z":1,"y":2}],"debug":false}`, string(b))
+
+	// setting an existing key keeps its position, a new key is appended
+	js.Set("name", "api")
+	js.Set("added", 1)
+	js.Get("server").Set("port", 8080)
+	js.Get("server").Set("tls", true)
+	b, _ = js.Encode()
+	assert.Equal(t, `{"name":"api","server":{"port":8080,"host":"a","tls":true},"tags":["x",{"z":1,"y":2}],"debug":false,"added":1}`, string(b))
+
+	// a deleted key moves to the end when set again
+	js.Del("server")
+	js.Set("server", "gone")
+	b, _ = js.Encode()
+	assert.Equal(t, `{"name":"api","tags":["x",{"z":1,"y":2}],"debug":false,"added":1,"server":"gone"}`, string(b))
+
+	// keys added around Set follow the recorded ones, sorted
+	js.MustMap()["b"] = 2
+	js.SetPath([]string{"a"}, 1)
+	b, _ = js.Encode()
+	assert.Equal(t, `{"name":"api","tags":["x",{"z":1,"y":2}],"debug":false,"added":1,"server":"gone","a":1,"b":2}`, string(b))
+
+	b, _ = js.Get("tags").EncodePretty()
+	assert.Equal(t, "[\n  \"x\",\n  {\n    \"z\": 1,\n    \"y\": 2\n  }\n]", string(b))
+
+	// other documents are unaffected
+	plain, _ := NewGson([]byte(`{"b":1,"a":2}`))
+	plain.Set("b", 3)
+	b, _ = plain.Encode()
+	assert.Equal(t, `{"a":2,"b":3}`, string(b))
+
+	_, err = NewOrdered([]byte(`{"a":`))
+	assert.NotEqual(t, nil, err)
+}
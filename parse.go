@@ -0,0 +1,223 @@
+package gson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// EventHandler receives the events emitted by Parse while it streams
+// through a JSON document. Returning an error from any callback stops
+// parsing and Parse returns that error.
+type EventHandler interface {
+	OnObjectStart() error
+	OnObjectEnd() error
+	OnArrayStart() error
+	OnArrayEnd() error
+	// OnKey is called with the name of each object member, before the
+	// events for its value
+	OnKey(key string) error
+	// OnValue is called for every scalar: a `string`, `json.Number`,
+	// `bool` or nil
+	OnValue(value interface{}) error
+}
+
+// Parse reads a single JSON value from `r` and reports its structure to
+// `handler` SAX-style, without building a `Gson` tree in memory
+func Parse(r io.Reader, handler EventHandler) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	type frame struct {
+		object bool
+		// the next token of this object is a member name
+		key bool
+	}
+	var stack []frame
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF && len(stack) > 0 {
+			return io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return err
+		}
+
+		if n := len(stack); n > 0 && stack[n-1].key {
+			if key, ok := tok.(string); ok {
+				stack[n-1].key = false
+				if err := handler.OnKey(key); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		opened := false
+		switch tok {
+		case json.Delim('{'):
+			stack = append(stack, frame{object: true, key: true})
+			opened = true
+			err = handler.OnObjectStart()
+		case json.Delim('['):
+			stack = append(stack, frame{})
+			opened = true
+			err = handler.OnArrayStart()
+		case json.Delim('}'):
+			stack = stack[:len(stack)-1]
+			err = handler.OnObjectEnd()
+		case json.Delim(']'):
+			stack = stack[:len(stack)-1]
+			err = handler.OnArrayEnd()
+		default:
+			err = handler.OnValue(tok)
+		}
+		if err != nil {
+			return err
+		}
+		if opened {
+			continue
+		}
+
+		// a complete value has been reported
+		if len(stack) == 0 {
+			return nil
+		}
+		if top := &stack[len(stack)-1]; top.object {
+			top.key = true
+		}
+	}
+}
+
+// Peek reports the kind of the JSON value at the start of `r` ("object",
+// "array", "string", "number", "bool" or "null") after reading only as far
+// as its first token. The returned reader replays everything consumed from
+// `r` followed by the rest of it, so the full content can be decoded next.
+//
+//	kind, r, err := Peek(body)
+//	if kind == "array" {
+//		...
+//	}
+func Peek(r io.Reader) (string, io.Reader, error) {
+	var buf bytes.Buffer
+	dec := json.NewDecoder(io.TeeReader(r, &buf))
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	replay := io.MultiReader(&buf, r)
+	if err != nil {
+		return "", replay, err
+	}
+
+	var kind string
+	switch tok.(type) {
+	case json.Delim:
+		if tok == json.Delim('{') {
+			kind = "object"
+		} else {
+			kind = "array"
+		}
+	case string:
+		kind = "string"
+	case json.Number:
+		kind = "number"
+	case bool:
+		kind = "bool"
+	case nil:
+		kind = "null"
+	}
+	return kind, replay, nil
+}
+
+// NewStrict is like NewGson but rejects documents in which any object has
+// a duplicate key, which encoding/json silently resolves by keeping the
+// last one. The error names the key and its location as a JSON Pointer.
+func NewStrict(body []byte) (*Gson, error) {
+	b := &treeBuilder{rejectDuplicates: true}
+	if err := Parse(bytes.NewReader(body), b); err != nil {
+		return nil, err
+	}
+	return &Gson{data: b.root}, nil
+}
+
+// treeBuilder is an EventHandler assembling the same values json.Decoder
+// produces with UseNumber
+type treeBuilder struct {
+	rejectDuplicates bool
+	// records the member order of every object, for NewOrdered
+	order *keyOrder
+
+	root  interface{}
+	stack []*buildFrame
+}
+
+type buildFrame struct {
+	object map[string]interface{}
+	array  []interface{}
+	// the member currently being decoded, for objects
+	key string
+}
+
+func (b *treeBuilder) OnObjectStart() error {
+	b.stack = append(b.stack, &buildFrame{object: make(map[string]interface{})})
+	return nil
+}
+
+func (b *treeBuilder) OnArrayStart() error {
+	b.stack = append(b.stack, &buildFrame{array: make([]interface{}, 0)})
+	return nil
+}
+
+func (b *treeBuilder) OnObjectEnd() error {
+	top := b.stack[len(b.stack)-1]
+	b.stack = b.stack[:len(b.stack)-1]
+	return b.OnValue(top.object)
+}
+
+func (b *treeBuilder) OnArrayEnd() error {
+	top := b.stack[len(b.stack)-1]
+	b.stack = b.stack[:len(b.stack)-1]
+	return b.OnValue(top.array)
+}
+
+func (b *treeBuilder) OnKey(key string) error {
+	top := b.stack[len(b.stack)-1]
+	top.key = key
+	if _, dup := top.object[key]; dup && b.rejectDuplicates {
+		return fmt.Errorf("duplicate key %q at %s", key, formatPointer(b.path()))
+	}
+	if b.order != nil {
+		b.order.add(top.object, key)
+	}
+	return nil
+}
+
+func (b *treeBuilder) OnValue(value interface{}) error {
+	if len(b.stack) == 0 {
+		b.root = value
+		return nil
+	}
+	top := b.stack[len(b.stack)-1]
+	if top.object != nil {
+		top.object[top.key] = value
+	} else {
+		top.array = append(top.array, value)
+	}
+	return nil
+}
+
+// path returns the location of the value currently being decoded
+func (b *treeBuilder) path() []string {
+	path := make([]string, 0, len(b.stack))
+	for _, f := range b.stack {
+		if f.object != nil {
+			path = append(path, f.key)
+		} else {
+			path = append(path, strconv.Itoa(len(f.array)))
+		}
+	}
+	return path
+}
@@ -0,0 +1,123 @@
+package gson
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"git.egret.io/go/assert"
+	"io"
+	"strings"
+	"testing"
+)
+
+type recorder struct {
+	events []string
+	stopOn string
+}
+
+func (r *recorder) record(ev string) error {
+	r.events = append(r.events, ev)
+	if ev == r.stopOn {
+		return errors.New("stop")
+	}
+	return nil
+}
+
+func (r *recorder) OnObjectStart() error        { return r.record("{") }
+func (r *recorder) OnObjectEnd() error          { return r.record("}") }
+func (r *recorder) OnArrayStart() error         { return r.record("[") }
+func (r *recorder) OnArrayEnd() error           { return r.record("]") }
+func (r *recorder) OnKey(key string) error      { return r.record("key:" + key) }
+func (r *recorder) OnValue(v interface{}) error { return r.record(fmt.Sprintf("%T:%v", v, v)) }
+
+func TestParse(t *testing.T) {
+	rec := &recorder{}
+	err := Parse(strings.NewReader(`{"a":[1,"x",{"b":null}],"c":{"d":true},"e":"key"} {"next":1}`), rec)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{
+		"{",
+		"key:a", "[", "json.Number:1", "string:x", "{", "key:b", "<nil>:<nil>", "}", "]",
+		"key:c", "{", "key:d", "bool:true", "}",
+		"key:e", "string:key",
+		"}",
+	}, rec.events)
+
+	rec = &recorder{}
+	assert.Equal(t, nil, Parse(bytes.NewBufferString(`"scalar"`), rec))
+	assert.Equal(t, []string{"string:scalar"}, rec.events)
+
+	rec = &recorder{stopOn: "key:b"}
+	err = Parse(strings.NewReader(`{"a":1,"b":2,"c":3}`), rec)
+	assert.Equal(t, "stop", err.Error())
+	assert.Equal(t, []string{"{", "key:a", "json.Number:1", "key:b"}, rec.events)
+
+	err = Parse(strings.NewReader(`{"a":[1,`), &recorder{})
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+
+	err = Parse(strings.NewReader(``), &recorder{})
+	assert.Equal(t, io.EOF, err)
+
+	err = Parse(strings.NewReader(`{"a" 1}`), &recorder{})
+	assert.NotEqual(t, nil, err)
+}
+
+func TestPeek(t *testing.T) {
+	cases := []struct {
+		body string
+		kind string
+	}{
+		{`  {"a": [1, 2]}`, "object"},
+		{`[{"a": 1}]`, "array"},
+		{`"str"`, "string"},
+		{`-1.5e3`, "number"},
+		{`true`, "bool"},
+		{`null`, "null"},
+	}
+	for _, tc := range cases {
+		kind, r, err := Peek(strings.NewReader(tc.body))
+		assert.Equal(t, nil, err)
+		assert.Equal(t, tc.kind, kind)
+
+		rest, _ := io.ReadAll(r)
+		assert.Equal(t, tc.body, string(rest))
+	}
+
+	// a large body is replayed in full after the peek
+	body := `[` + strings.Repeat(`"xxxxxxxxxx",`, 1000) + `0]`
+	kind, r, err := Peek(strings.NewReader(body))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "array", kind)
+	js, err := NewFromReader(r)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1001, len(js.MustArray()))
+
+	_, _, err = Peek(strings.NewReader(` `))
+	assert.Equal(t, io.EOF, err)
+	_, _, err = Peek(strings.NewReader(`}`))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestNewStrict(t *testing.T) {
+	body := `{"a":[1,{"b":null}],"c":{"d~/":true,"e":"x"},"n":1.50}`
+	js, err := NewStrict([]byte(body))
+	assert.Equal(t, nil, err)
+	expected, _ := NewGson([]byte(body))
+	assert.Equal(t, expected.Interface(), js.Interface())
+
+	_, err = NewStrict([]byte(`{"a":1,"a":2}`))
+	assert.Equal(t, `duplicate key "a" at /a`, err.Error())
+
+	_, err = NewStrict([]byte(`{"x":[{"ok":1},{"c/d":1,"c/d":{}}]}`))
+	assert.Equal(t, `duplicate key "c/d" at /x/1/c~1d`, err.Error())
+
+	// the same key in different objects is fine
+	_, err = NewStrict([]byte(`{"a":{"a":1},"b":[{"a":1},{"a":2}]}`))
+	assert.Equal(t, nil, err)
+
+	js, err = NewStrict([]byte(`[]`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []interface{}{}, js.MustArray())
+
+	_, err = NewStrict([]byte(`{"a":`))
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,84 @@
+package gson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Path is a parsed path expression ready to be applied to any number of
+// documents with GetCompiled
+type Path struct {
+	segments []pathSegment
+}
+
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// CompilePath parses a path expression such as `users[0].address.city`
+// once, for repeated use with GetCompiled. Object keys are separated by
+// dots and array indices written in brackets; keys may not contain '.',
+// '[' or ']'. The empty expression denotes the node itself.
+//
+//	p, err := CompilePath("items[0].id")
+//	for _, js := range docs {
+//		fmt.Println(js.GetCompiled(p).MustString())
+//	}
+func CompilePath(expr string) (*Path, error) {
+	p := &Path{}
+	for pos := 0; pos < len(expr); {
+		if expr[pos] == '[' {
+			end := strings.IndexByte(expr[pos:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '[' at offset %d in path %q", pos, expr)
+			}
+			index, err := strconv.Atoi(expr[pos+1 : pos+end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid index %q at offset %d in path %q", expr[pos+1:pos+end], pos, expr)
+			}
+			p.segments = append(p.segments, pathSegment{index: index, isIndex: true})
+			pos += end + 1
+		} else {
+			end := strings.IndexAny(expr[pos:], ".[]")
+			if end < 0 {
+				end = len(expr) - pos
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("expected a key at offset %d in path %q", pos, expr)
+			}
+			p.segments = append(p.segments, pathSegment{key: expr[pos : pos+end]})
+			pos += end
+		}
+
+		if pos < len(expr) {
+			switch expr[pos] {
+			case '.':
+				pos++
+				if pos == len(expr) || expr[pos] == '[' {
+					return nil, fmt.Errorf("expected a key at offset %d in path %q", pos, expr)
+				}
+			case '[':
+			default:
+				return nil, fmt.Errorf("unexpected %q at offset %d in path %q", expr[pos], pos, expr)
+			}
+		}
+	}
+	return p, nil
+}
+
+// GetCompiled returns a pointer to a new `Gson` object for the value at the
+// compiled path `p`, navigating with Get and GetIndex
+func (self *Gson) GetCompiled(p *Path) *Gson {
+	jin := self
+	for _, s := range p.segments {
+		if s.isIndex {
+			jin = jin.GetIndex(s.index)
+		} else {
+			jin = jin.Get(s.key)
+		}
+	}
+	return jin
+}
@@ -0,0 +1,36 @@
+package gson
+
+import (
+	"git.egret.io/go/assert"
+	"testing"
+)
+
+func TestCompilePath(t *testing.T) {
+	p, err := CompilePath("users[1].address.city")
+	assert.Equal(t, nil, err)
+
+	for i, body := range []string{
+		`{"users":[{},{"address":{"city":"Oslo"}}]}`,
+		`{"users":[{},{"address":{"city":"Rome"}}]}`,
+	} {
+		js, _ := NewGson([]byte(body))
+		assert.Equal(t, []string{"Oslo", "Rome"}[i], js.GetCompiled(p).MustString())
+	}
+
+	js, _ := NewGson([]byte(`[[1,{"a-b":[true]}]]`))
+	p, err = CompilePath("[0][1].a-b[0]")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, js.GetCompiled(p).MustBool())
+
+	p, err = CompilePath("")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, js, js.GetCompiled(p))
+
+	p, _ = CompilePath("missing[3].x")
+	assert.Equal(t, nil, js.GetCompiled(p).Interface())
+
+	for _, expr := range []string{"a.", ".a", "a..b", "a[", "a[x]", "a[-1]", "a.[0]", "a]b", "a[0]b"} {
+		_, err := CompilePath(expr)
+		assert.NotEqual(t, nil, err, expr)
+	}
+}
@@ -0,0 +1,170 @@
+package gson
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// The operations UpsertPlan produces and ApplyPlan understands
+const (
+	// OpSet writes Operation.Value at Operation.Path
+	OpSet = "set"
+	// OpDel removes the member or element at Operation.Path
+	OpDel = "del"
+)
+
+// Operation is one step of a plan: set a value at, or delete, the node at
+// Path, given as a JSON Pointer
+type Operation struct {
+	Op    string
+	Path  string
+	Value interface{}
+}
+
+// UpsertPlan returns the operations that turn the document into `target`:
+// a del for every object member only the document has, a set for every
+// member only `target` has, and a set for every value that differs,
+// descending into objects present on both sides and into arrays of the
+// same length so that only the changed parts are written. An array whose
+// length changes is set whole. Operations come in key order, and values
+// are copies taken from `target`.
+//
+// applying the plan with ApplyPlan makes the document equal to `target`.
+func (self *Gson) UpsertPlan(target *Gson) ([]Operation, error) {
+	a, err := jsonValue(self.data)
+	if err != nil {
+		return nil, err
+	}
+	b, err := jsonValue(target.data)
+	if err != nil {
+		return nil, err
+	}
+	return upsertPlan(nil, nil, a, b), nil
+}
+
+func upsertPlan(ops []Operation, path []string, a, b interface{}) []Operation {
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, k := range mergedKeys(x, y) {
+			p := append(path, k)
+			xv, inA := x[k]
+			yv, inB := y[k]
+			switch {
+			case !inB:
+				ops = append(ops, Operation{Op: OpDel, Path: formatPointer(p)})
+			case !inA:
+				ops = append(ops, Operation{Op: OpSet, Path: formatPointer(p), Value: yv})
+			default:
+				ops = upsertPlan(ops, p, xv, yv)
+			}
+		}
+		return ops
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			break
+		}
+		for i := range x {
+			ops = upsertPlan(ops, append(path, strconv.Itoa(i)), x[i], y[i])
+		}
+		return ops
+	}
+	if valuesEqual(a, b) {
+		return ops
+	}
+	return append(ops, Operation{Op: OpSet, Path: formatPointer(path), Value: b})
+}
+
+// ApplyPlan applies `ops`, as produced by UpsertPlan or built by hand, in
+// order. A set may replace any existing node, add an object member, or
+// append to an array when its last segment is the array's length (or `-`);
+// a del must name an existing member or element, and removing an element
+// shifts the ones after it. The path "" stands for the whole document.
+//
+// the plan is applied atomically: it runs on a copy which replaces the
+// document only if every operation succeeds. Otherwise the document is
+// left unchanged and the error names the index of the failing operation.
+func (self *Gson) ApplyPlan(ops []Operation) error {
+	doc := deepCopy(self.data)
+	for i, op := range ops {
+		tokens, err := parsePointer(op.Path)
+		if err == nil {
+			switch op.Op {
+			case OpSet:
+				doc, err = planSet(doc, tokens, deepCopy(op.Value))
+			case OpDel:
+				doc, err = planDel(doc, tokens)
+			default:
+				err = fmt.Errorf("unknown op %q", op.Op)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("operation %d (%s %q): %v", i, op.Op, op.Path, err)
+		}
+	}
+	self.data = doc
+	return nil
+}
+
+// planSet writes `val` at `tokens` below `doc` and returns the new root
+func planSet(doc interface{}, tokens []string, val interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return val, nil
+	}
+	parentPath, last := tokens[:len(tokens)-1], tokens[len(tokens)-1]
+	parent, ok := pointerGet(doc, parentPath)
+	if !ok {
+		return nil, errors.New("parent not found")
+	}
+	switch c := parent.(type) {
+	case map[string]interface{}:
+		c[last] = val
+		return doc, nil
+	case []interface{}:
+		if last == "-" {
+			return planSet(doc, parentPath, append(c, val))
+		}
+		i, ok := pointerIndex(last)
+		if !ok || i > len(c) {
+			return nil, fmt.Errorf("index %q out of range (length %d)", last, len(c))
+		}
+		if i == len(c) {
+			return planSet(doc, parentPath, append(c, val))
+		}
+		c[i] = val
+		return doc, nil
+	}
+	return nil, fmt.Errorf("parent is %s, not an object or array", kindOf(parent))
+}
+
+// planDel removes the node at `tokens` below `doc` and returns the new root
+func planDel(doc interface{}, tokens []string) (interface{}, error) {
+	if len(tokens) == 0 {
+		return nil, errors.New("cannot delete the whole document")
+	}
+	parentPath, last := tokens[:len(tokens)-1], tokens[len(tokens)-1]
+	parent, ok := pointerGet(doc, parentPath)
+	if !ok {
+		return nil, errors.New("parent not found")
+	}
+	switch c := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := c[last]; !ok {
+			return nil, errors.New("member not found")
+		}
+		delete(c, last)
+		return doc, nil
+	case []interface{}:
+		i, ok := pointerIndex(last)
+		if !ok || i >= len(c) {
+			return nil, fmt.Errorf("index %q out of range (length %d)", last, len(c))
+		}
+		return planSet(doc, parentPath, append(c[:i:i], c[i+1:]...))
+	}
+	return nil, fmt.Errorf("parent is %s, not an object or array", kindOf(parent))
+}
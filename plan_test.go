@@ -0,0 +1,91 @@
+package gson
+
+import (
+	"encoding/json"
+	"git.egret.io/go/assert"
+	"testing"
+)
+
+func TestUpsertPlan(t *testing.T) {
+	cur, err := NewGson([]byte(`{"name":"web","replicas":2,"labels":{"app":"web","old":"x"},"ports":[80,443],"env":["A"],"same":{"v":1.0}}`))
+	assert.Equal(t, nil, err)
+	want, err := NewGson([]byte(`{"name":"web","replicas":3,"labels":{"app":"web","tier":"front"},"ports":[80,8443],"env":["A","B"],"same":{"v":1},"new":{"on":true}}`))
+	assert.Equal(t, nil, err)
+
+	ops, err := cur.UpsertPlan(want)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []Operation{
+		{Op: OpSet, Path: "/env", Value: []interface{}{"A", "B"}},
+		{Op: OpDel, Path: "/labels/old"},
+		{Op: OpSet, Path: "/labels/tier", Value: "front"},
+		{Op: OpSet, Path: "/new", Value: map[string]interface{}{"on": true}},
+		{Op: OpSet, Path: "/ports/1", Value: json.Number("8443")},
+		{Op: OpSet, Path: "/replicas", Value: json.Number("3")},
+	}, ops)
+
+	ops, _ = cur.UpsertPlan(cur)
+	assert.Equal(t, 0, len(ops))
+
+	scalar, _ := NewGson([]byte(`"x"`))
+	ops, _ = cur.UpsertPlan(scalar)
+	assert.Equal(t, []Operation{{Op: OpSet, Path: "", Value: "x"}}, ops)
+}
+
+func TestApplyPlan(t *testing.T) {
+	cur, _ := NewGson([]byte(`{"name":"web","replicas":2,"labels":{"app":"web","old":"x"},"ports":[80,443],"env":["A"]}`))
+	want, _ := NewGson([]byte(`{"name":"web","replicas":3,"labels":{"app":"web","tier":"front"},"ports":[80,8443],"env":["A","B"],"new":{"on":true}}`))
+
+	ops, err := cur.UpsertPlan(want)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, cur.ApplyPlan(ops))
+	assert.Equal(t, true, cur.EqualValue(want.Interface()))
+
+	// values are copied in, not shared with the plan
+	ops[0].Value.([]interface{})[0] = "changed"
+	assert.Equal(t, "A", cur.Get("env").GetIndex(0).MustString())
+
+	js, _ := NewGson([]byte(`{"list":[1,2,3],"a/b":{}}`))
+	assert.Equal(t, nil, js.ApplyPlan([]Operation{
+		{Op: OpSet, Path: "/list/-", Value: 4},
+		{Op: OpSet, Path: "/list/4", Value: 5},
+		{Op: OpDel, Path: "/list/0"},
+		{Op: OpSet, Path: "/a~1b/c", Value: "x"},
+	}))
+	b, _ := js.Encode()
+	assert.Equal(t, `{"a/b":{"c":"x"},"list":[2,3,4,5]}`, string(b))
+
+	// a failing plan leaves the document untouched
+	err = js.ApplyPlan([]Operation{
+		{Op: OpDel, Path: "/list/0"},
+		{Op: OpSet, Path: "/missing/deep", Value: 1},
+	})
+	assert.Equal(t, `operation 1 (set "/missing/deep"): parent not found`, err.Error())
+	b, _ = js.Encode()
+	assert.Equal(t, `{"a/b":{"c":"x"},"list":[2,3,4,5]}`, string(b))
+
+	for _, op := range []Operation{
+		{Op: OpDel, Path: "/nope"},
+		{Op: OpDel, Path: "/list/9"},
+		{Op: OpSet, Path: "/list/6", Value: 1},
+		{Op: OpSet, Path: "/list/01", Value: 1},
+		{Op: OpSet, Path: "/list/-0", Value: 1},
+		{Op: OpSet, Path: "/list/0/x", Value: 1},
+		{Op: OpDel, Path: ""},
+		{Op: "move", Path: "/list"},
+		{Op: OpSet, Path: "no-slash"},
+	} {
+		assert.NotEqual(t, nil, js.ApplyPlan([]Operation{op}), op)
+	}
+
+	assert.Equal(t, nil, js.ApplyPlan([]Operation{{Op: OpSet, Path: "", Value: []interface{}{}}}))
+	assert.Equal(t, []interface{}{}, js.Interface())
+
+	lazy, _ := NewLazy([]byte(`{"labels":{"app":"web","old":"x"},"ports":[80]}`))
+	assert.Equal(t, nil, lazy.ApplyPlan([]Operation{
+		{Op: OpSet, Path: "/labels/tier", Value: "front"},
+		{Op: OpDel, Path: "/labels/old"},
+		{Op: OpSet, Path: "/ports/-", Value: 443},
+	}))
+	b, _ = lazy.Encode()
+	assert.Equal(t, `{"labels":{"app":"web","tier":"front"},"ports":[80,443]}`, string(b))
+}
@@ -0,0 +1,65 @@
+package gson
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
+
+// pooledDecoder is a json.Decoder whose input can be swapped, so that its
+// internal buffer is reused across calls instead of allocated per parse
+type pooledDecoder struct {
+	src bytes.Reader
+	dec *json.Decoder
+}
+
+// maxPooledInput caps the size of the inputs whose decoders go back into
+// the pool: a decoder's buffer grows to hold its largest input and is never
+// shrunk, so one large document would otherwise pin that memory for good
+const maxPooledInput = 64 << 10
+
+var decoderPool = sync.Pool{
+	New: func() interface{} {
+		d := new(pooledDecoder)
+		d.dec = json.NewDecoder(&d.src)
+		d.dec.UseNumber()
+		return d
+	},
+}
+
+// decodeBytes decodes the first JSON value in `p` into `v` with UseNumber,
+// using a pooled decoder
+func decodeBytes(p []byte, v *interface{}) error {
+	d := decoderPool.Get().(*pooledDecoder)
+	d.src.Reset(p)
+	err := d.dec.Decode(v)
+	d.src.Reset(nil)
+
+	if err != nil {
+		// a reused decoder reports offsets relative to everything it has
+		// ever read, so produce the error from a fresh one instead
+		dec := json.NewDecoder(bytes.NewReader(p))
+		dec.UseNumber()
+		return dec.Decode(v)
+	}
+
+	// trailing input left in the decoder's buffer would otherwise be read
+	// as the start of the next document, and large inputs are not pooled
+	// (see maxPooledInput)
+	if rest, ok := d.dec.Buffered().(*bytes.Reader); ok && isSpace(rest) && len(p) <= maxPooledInput {
+		decoderPool.Put(d)
+	}
+	return nil
+}
+
+func isSpace(r *bytes.Reader) bool {
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return true
+		}
+		if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			return false
+		}
+	}
+}
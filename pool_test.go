@@ -0,0 +1,62 @@
+package gson
+
+import (
+	"bytes"
+	"encoding/json"
+	"git.egret.io/go/assert"
+	"strings"
+	"testing"
+)
+
+var benchBody = []byte(`{"id":12345,"name":"simplejson","tags":["a","b","c"],"nested":{"ok":true,"ratio":0.5}}`)
+
+func TestPooledDecoding(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		js, err := NewGson([]byte(`{"a":1}  garbage`))
+		assert.Equal(t, nil, err)
+		assert.Equal(t, 1, js.Get("a").MustInt())
+
+		// leftovers of the previous input must not leak into the next one
+		js, err = NewGson([]byte(` [2] `))
+		assert.Equal(t, nil, err)
+		assert.Equal(t, 2, js.GetIndex(0).MustInt())
+	}
+
+	NewGson(benchBody)
+	_, err := NewGson([]byte(`{"a" 1}`))
+	serr, ok := err.(*json.SyntaxError)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, int64(6), serr.Offset)
+
+	// decoders that saw a large input are dropped rather than pooled
+	large := []byte(`{"s":"` + strings.Repeat("x", maxPooledInput) + `"}`)
+	js, err := NewGson(large)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, maxPooledInput, len(js.Get("s").MustString()))
+	js, err = NewGson([]byte(`{"a":3}`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, js.Get("a").MustInt())
+}
+
+func BenchmarkNewGson(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewGson(benchBody); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkNewGsonUnpooled decodes as NewGson did before decoders were
+// pooled, for comparison
+func BenchmarkNewGsonUnpooled(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var v interface{}
+		dec := json.NewDecoder(bytes.NewBuffer(benchBody))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
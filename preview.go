@@ -0,0 +1,164 @@
+package gson
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// EncodePrettyTruncated returns its data pretty-printed like EncodePretty,
+// but with long strings cut short (`"abc…"`) and long arrays elided after
+// their first elements (a final `"[… 500 more]"` element), tightening both
+// limits until the output fits in roughly `maxLen` bytes. It is meant for
+// logs: the result shows the document's shape but cannot be round-tripped.
+func (self *Gson) EncodePrettyTruncated(maxLen int) ([]byte, error) {
+	v, err := jsonValue(self.data)
+	if err != nil {
+		return nil, err
+	}
+
+	maxString, maxArray := maxLen, maxLen
+	if maxString < 0 {
+		maxString, maxArray = 0, 0
+	}
+	for {
+		b, err := json.MarshalIndent(elide(v, maxString, maxArray), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if len(b) <= maxLen || (maxString <= 1 && maxArray <= 1) {
+			return b, nil
+		}
+		maxString = (maxString + 1) / 2
+		maxArray = (maxArray + 1) / 2
+	}
+}
+
+// EncodeDepth returns its marshaled data with only the top `maxDepth`
+// levels of objects and arrays spelled out; non-empty containers nested
+// any deeper are replaced by the strings "{…}" and "[…]". Like
+// EncodePrettyTruncated this is a preview for humans, not a round trip.
+//
+//	js.EncodeDepth(1) // {"user":"{…}","count":3}
+func (self *Gson) EncodeDepth(maxDepth int) ([]byte, error) {
+	v, err := jsonValue(self.data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(capDepth(v, maxDepth))
+}
+
+// EncodePreview combines array capping with EncodeDepth for one-call log
+// previews: arrays keep only their first `maxArray` elements followed by a
+// `"… (M more)"` string marker, and containers below `maxDepth` levels are
+// replaced by placeholders as in EncodeDepth.
+//
+//	js.EncodePreview(2, 3) // {"items":[{"id":1},{"id":2},"… (98 more)"]}
+func (self *Gson) EncodePreview(maxArray, maxDepth int) ([]byte, error) {
+	v, err := jsonValue(self.data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(capDepth(capArrays(v, maxArray), maxDepth))
+}
+
+// capArrays returns a copy of `v` with arrays limited to `maxArray`
+// elements plus a marker
+func capArrays(v interface{}, maxArray int) interface{} {
+	switch x := v.(type) {
+	case []interface{}:
+		n := len(x)
+		if n > maxArray {
+			n = maxArray
+		}
+		if n < 0 {
+			n = 0
+		}
+		a := make([]interface{}, n, n+1)
+		for i := range a {
+			a[i] = capArrays(x[i], maxArray)
+		}
+		if n < len(x) {
+			a = append(a, fmt.Sprintf("… (%d more)", len(x)-n))
+		}
+		return a
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, e := range x {
+			m[k] = capArrays(e, maxArray)
+		}
+		return m
+	}
+	return v
+}
+
+// capDepth returns a copy of `v` with containers below `depth` levels
+// replaced by placeholders
+func capDepth(v interface{}, depth int) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if len(x) == 0 {
+			return x
+		}
+		if depth <= 0 {
+			return "{…}"
+		}
+		m := make(map[string]interface{}, len(x))
+		for k, e := range x {
+			m[k] = capDepth(e, depth-1)
+		}
+		return m
+	case []interface{}:
+		if len(x) == 0 {
+			return x
+		}
+		if depth <= 0 {
+			return "[…]"
+		}
+		a := make([]interface{}, len(x))
+		for i, e := range x {
+			a[i] = capDepth(e, depth-1)
+		}
+		return a
+	}
+	return v
+}
+
+// elide returns a copy of `v` in which strings are limited to `maxString`
+// runes and arrays to `maxArray` elements plus a marker
+func elide(v interface{}, maxString, maxArray int) interface{} {
+	switch x := v.(type) {
+	case string:
+		if utf8.RuneCountInString(x) <= maxString {
+			return x
+		}
+		n := 0
+		for i := range x {
+			if n == maxString {
+				return x[:i] + "…"
+			}
+			n++
+		}
+		return x
+	case []interface{}:
+		n := len(x)
+		if n > maxArray {
+			n = maxArray
+		}
+		a := make([]interface{}, n, n+1)
+		for i := range a {
+			a[i] = elide(x[i], maxString, maxArray)
+		}
+		if n < len(x) {
+			a = append(a, fmt.Sprintf("[… %d more]", len(x)-n))
+		}
+		return a
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, e := range x {
+			m[k] = elide(e, maxString, maxArray)
+		}
+		return m
+	}
+	return v
+}
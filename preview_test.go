@@ -0,0 +1,82 @@
+package gson
+
+import (
+	"git.egret.io/go/assert"
+	"strings"
+	"testing"
+)
+
+func TestEncodePrettyTruncated(t *testing.T) {
+	js := New()
+	js.Set("text", strings.Repeat("ü", 1000))
+	list := make([]interface{}, 500)
+	for i := range list {
+		list[i] = i
+	}
+	js.Set("list", list)
+	js.Set("id", "abc")
+
+	b, err := js.EncodePrettyTruncated(200)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, len(b) <= 200)
+	s := string(b)
+	assert.Equal(t, true, strings.Contains(s, `"id": "abc"`))
+	assert.Equal(t, true, strings.Contains(s, "…\""))
+	assert.Equal(t, true, strings.Contains(s, " more]\""))
+
+	// small documents are printed in full
+	small, _ := NewGson([]byte(`{"a":[1,2],"b":"xyz"}`))
+	b, err = small.EncodePrettyTruncated(1000)
+	assert.Equal(t, nil, err)
+	full, _ := small.EncodePretty()
+	assert.Equal(t, string(full), string(b))
+
+	b, err = small.EncodePrettyTruncated(30)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "{\n  \"a\": [\n    1,\n    \"[… 1 more]\"\n  ],\n  \"b\": \"x…\"\n}", string(b))
+
+	b, err = small.EncodePrettyTruncated(-1)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "{\n  \"a\": [\n    \"[… 2 more]\"\n  ],\n  \"b\": \"…\"\n}", string(b))
+}
+
+func TestEncodeDepth(t *testing.T) {
+	js, err := NewGson([]byte(`{"count":3,"user":{"name":"ann","tags":["a"],"none":{}},"list":[[1],2]}`))
+	assert.Equal(t, nil, err)
+
+	cases := []struct {
+		depth int
+		want  string
+	}{
+		{0, `"{…}"`},
+		{1, `{"count":3,"list":"[…]","user":"{…}"}`},
+		{2, `{"count":3,"list":["[…]",2],"user":{"name":"ann","none":{},"tags":"[…]"}}`},
+		{3, `{"count":3,"list":[[1],2],"user":{"name":"ann","none":{},"tags":["a"]}}`},
+	}
+	for _, tc := range cases {
+		b, err := js.EncodeDepth(tc.depth)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, tc.want, string(b))
+	}
+
+	// the document itself is untouched
+	assert.Equal(t, "ann", js.GetPath("user", "name").MustString())
+}
+
+func TestEncodePreview(t *testing.T) {
+	js, err := NewGson([]byte(`{"items":[{"id":1,"tags":["a","b","c"]},{"id":2,"tags":[]},{"id":3},{"id":4}],"meta":{"deep":{"deeper":{"x":1}}},"few":[1,2]}`))
+	assert.Equal(t, nil, err)
+
+	b, err := js.EncodePreview(2, 3)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"few":[1,2],"items":[{"id":1,"tags":"[…]"},{"id":2,"tags":[]},"… (2 more)"],"meta":{"deep":{"deeper":"{…}"}}}`, string(b))
+
+	b, _ = js.EncodePreview(2, 4)
+	assert.Equal(t, `{"few":[1,2],"items":[{"id":1,"tags":["a","b","… (1 more)"]},{"id":2,"tags":[]},"… (2 more)"],"meta":{"deep":{"deeper":{"x":1}}}}`, string(b))
+
+	b, _ = js.EncodePreview(0, 1)
+	assert.Equal(t, `{"few":"[…]","items":"[…]","meta":"{…}"}`, string(b))
+
+	b, _ = js.EncodePreview(0, 5)
+	assert.Equal(t, `{"few":["… (2 more)"],"items":["… (4 more)"],"meta":{"deep":{"deeper":{"x":1}}}}`, string(b))
+}
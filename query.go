@@ -0,0 +1,178 @@
+package gson
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EncodeQuery returns a flat object encoded as a URL query string, keys
+// sorted and escaped. Values are rendered as AsString does (null becomes
+// an empty value); nested objects and arrays are an error.
+//
+//	q, err := js.EncodeQuery() // "limit=10&q=go+json"
+func (self *Gson) EncodeQuery() (string, error) {
+	m, err := self.Map()
+	if err != nil {
+		return "", errors.New("query encoding requires an object")
+	}
+	values := make(url.Values, len(m))
+	for k := range m {
+		v := expandMember(m, k)
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			return "", fmt.Errorf("value of %q is not a scalar", k)
+		}
+		values.Set(k, (&Gson{data: v}).AsString())
+	}
+	return values.Encode(), nil
+}
+
+// ToNestedQuery returns an object encoded as URL query values using the
+// bracket convention PHP and Rails style APIs expect. Top-level members
+// keep their key, object members are appended as `[key]` and array
+// elements as `[index]`, counting from 0, so `{"a":{"b":["x"]}}` becomes
+// `a[b][0]=x`. Scalars are rendered as AsString does (null becomes an
+// empty value); empty objects and arrays produce no values. Keys holding
+// a bracket can't be told apart from nesting, and empty keys from the
+// `a[]` append form, so both are an error.
+//
+//	values, err := js.ToNestedQuery()
+//	req.URL.RawQuery = values.Encode()
+func (self *Gson) ToNestedQuery() (url.Values, error) {
+	m, err := self.Map()
+	if err != nil {
+		return nil, errors.New("query encoding requires an object")
+	}
+	values := make(url.Values)
+	for _, k := range sortedKeys(m) {
+		if err := nestedQuery(values, "", k, m[k]); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// nestedQuery adds `v` to `values` under `prefix` extended with the
+// segment `seg`
+func nestedQuery(values url.Values, prefix, seg string, v interface{}) error {
+	if seg == "" || strings.ContainsAny(seg, "[]") {
+		return fmt.Errorf("key %q cannot be bracket encoded", seg)
+	}
+	key := seg
+	if prefix != "" {
+		key = prefix + "[" + seg + "]"
+	}
+
+	switch c := expandLazy(v).(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(c) {
+			if err := nestedQuery(values, key, k, c[k]); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, e := range c {
+			if err := nestedQuery(values, key, strconv.Itoa(i), e); err != nil {
+				return err
+			}
+		}
+	default:
+		values.Add(key, (&Gson{data: v}).AsString())
+	}
+	return nil
+}
+
+// NewFromForm returns a pointer to a new `Gson` object built from the
+// form-urlencoded `body`. Bracketed keys rebuild nested structure:
+// `a[b]=1` sets member "b" of object "a", and `a[]=2` appends to array
+// "a" (`a[][b]=3` appends a new object each time). Repeating a key turns its
+// value into an array. All values are strings.
+func NewFromForm(body []byte) (*Gson, error) {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var root interface{} = make(map[string]interface{})
+	for _, k := range keys {
+		segs := splitFormKey(k)
+		for _, v := range values[k] {
+			if root, err = formInsert(root, segs, v); err != nil {
+				return nil, fmt.Errorf("form key %q: %w", k, err)
+			}
+		}
+	}
+	return &Gson{data: root}, nil
+}
+
+// splitFormKey splits `a[b][]` into "a", "b" and ""; keys that are not
+// well formed bracket paths are kept whole
+func splitFormKey(key string) []string {
+	i := strings.IndexByte(key, '[')
+	if i <= 0 || !strings.HasSuffix(key, "]") {
+		return []string{key}
+	}
+	segs := []string{key[:i]}
+	for rest := key[i:]; len(rest) > 0; {
+		j := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || j < 0 || strings.IndexByte(rest[1:j], '[') >= 0 {
+			return []string{key}
+		}
+		segs = append(segs, rest[1:j])
+		rest = rest[j+1:]
+	}
+	return segs
+}
+
+var errFormConflict = errors.New("conflicts with another key")
+
+// formInsert stores `val` at `segs` below `node` and returns the new node
+func formInsert(node interface{}, segs []string, val string) (interface{}, error) {
+	if len(segs) == 0 {
+		switch x := node.(type) {
+		case nil:
+			return val, nil
+		case string:
+			return []interface{}{x, val}, nil
+		case []interface{}:
+			return append(x, val), nil
+		}
+		return nil, errFormConflict
+	}
+
+	if segs[0] == "" {
+		a, ok := node.([]interface{})
+		if !ok && node != nil {
+			return nil, errFormConflict
+		}
+		elem, err := formInsert(nil, segs[1:], val)
+		if err != nil {
+			return nil, err
+		}
+		return append(a, elem), nil
+	}
+
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		if node != nil {
+			return nil, errFormConflict
+		}
+		m = make(map[string]interface{})
+	}
+	v, err := formInsert(m[segs[0]], segs[1:], val)
+	if err != nil {
+		return nil, err
+	}
+	m[segs[0]] = v
+	return m, nil
+}
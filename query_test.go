@@ -0,0 +1,95 @@
+package gson
+
+import (
+	"git.egret.io/go/assert"
+	"testing"
+)
+
+func TestEncodeQuery(t *testing.T) {
+	js, err := NewGson([]byte(`{"q":"go json","limit":10,"exact":true,"sort":null,"a&b":"c=d"}`))
+	assert.Equal(t, nil, err)
+
+	q, err := js.EncodeQuery()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "a%26b=c%3Dd&exact=true&limit=10&q=go+json&sort=", q)
+
+	q, err = New().EncodeQuery()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "", q)
+
+	nested, _ := NewGson([]byte(`{"a":[1]}`))
+	_, err = nested.EncodeQuery()
+	assert.NotEqual(t, nil, err)
+
+	lazy, _ := NewLazy([]byte(`{"q":"x","a":{"b":1}}`))
+	_, err = lazy.EncodeQuery()
+	assert.Equal(t, `value of "a" is not a scalar`, err.Error())
+
+	arr, _ := NewGson([]byte(`[1]`))
+	_, err = arr.EncodeQuery()
+	assert.NotEqual(t, nil, err)
+}
+
+func TestToNestedQuery(t *testing.T) {
+	js, err := NewGson([]byte(`{
+		"q": "go json",
+		"filter": {"tags": ["a", "b"], "range": {"min": 1, "max": 2.5}, "off": false},
+		"items": [{"id": 1}, {"id": 2, "opt": null}],
+		"empty": {},
+		"none": []
+	}`))
+	assert.Equal(t, nil, err)
+
+	values, err := js.ToNestedQuery()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "a", values.Get("filter[tags][0]"))
+	assert.Equal(t, "b", values.Get("filter[tags][1]"))
+	assert.Equal(t, "2.5", values.Get("filter[range][max]"))
+	assert.Equal(t, "false", values.Get("filter[off]"))
+	assert.Equal(t, "2", values.Get("items[1][id]"))
+	assert.Equal(t, []string{""}, values["items[1][opt]"])
+	assert.Equal(t, 9, len(values))
+	assert.Equal(t, "filter%5Boff%5D=false&filter%5Brange%5D%5Bmax%5D=2.5&filter%5Brange%5D%5Bmin%5D=1"+
+		"&filter%5Btags%5D%5B0%5D=a&filter%5Btags%5D%5B1%5D=b&items%5B0%5D%5Bid%5D=1"+
+		"&items%5B1%5D%5Bid%5D=2&items%5B1%5D%5Bopt%5D=&q=go+json", values.Encode())
+
+	lazy, _ := NewLazy([]byte(`{"filter":{"tags":["a"],"range":{"min":1}}}`))
+	values, err = lazy.ToNestedQuery()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "filter%5Brange%5D%5Bmin%5D=1&filter%5Btags%5D%5B0%5D=a", values.Encode())
+
+	bad, _ := NewGson([]byte(`{"a":{"b[c]":1}}`))
+	_, err = bad.ToNestedQuery()
+	assert.Equal(t, `key "b[c]" cannot be bracket encoded`, err.Error())
+
+	arr, _ := NewGson([]byte(`[1]`))
+	_, err = arr.ToNestedQuery()
+	assert.Equal(t, "query encoding requires an object", err.Error())
+
+	for _, raw := range []string{`{"":{"b":1},"b":2}`, `{"a":{"":1}}`, `{"":1}`} {
+		empty, _ := NewGson([]byte(raw))
+		_, err = empty.ToNestedQuery()
+		assert.Equal(t, `key "" cannot be bracket encoded`, err.Error(), raw)
+	}
+}
+
+func TestNewFromForm(t *testing.T) {
+	js, err := NewFromForm([]byte(`name=ann&tag=a&tag=b&user[age]=30&user[address][city]=Oslo` +
+		`&ids[]=1&ids[]=2&items[][id]=x&items[][id]=y&odd[=1&q=go+json%21`))
+	assert.Equal(t, nil, err)
+
+	b, _ := js.Encode()
+	assert.Equal(t, `{"ids":["1","2"],"items":[{"id":"x"},{"id":"y"}],"name":"ann","odd[":"1",`+
+		`"q":"go json!","tag":["a","b"],"user":{"address":{"city":"Oslo"},"age":"30"}}`, string(b))
+
+	_, err = NewFromForm([]byte(`a=1&a[b]=2`))
+	assert.NotEqual(t, nil, err)
+	_, err = NewFromForm([]byte(`a[]=1&a[b]=2`))
+	assert.NotEqual(t, nil, err)
+	_, err = NewFromForm([]byte(`a=%zz`))
+	assert.NotEqual(t, nil, err)
+
+	js, err = NewFromForm(nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(js.MustMap()))
+}
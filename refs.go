@@ -0,0 +1,80 @@
+package gson
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ResolveRefs returns a pointer to a new `Gson` object in which every JSON
+// Reference object, `{"$ref": "#/path/to/node"}`, has been replaced by a
+// copy of the node its fragment points to, resolved as a JSON Pointer
+// against the document root. References inside the referenced nodes are
+// resolved as well; a reference that leads back to itself is an error.
+//
+// only internal references (starting with #) are followed, other `$ref`
+// objects are kept as they are. As in JSON Reference, members next to
+// `$ref` are ignored and dropped along with the reference object.
+func (self *Gson) ResolveRefs() (*Gson, error) {
+	r := refResolver{root: self.data, active: map[string]bool{}}
+	v, err := r.resolve(self.data)
+	if err != nil {
+		return nil, err
+	}
+	return &Gson{data: v}, nil
+}
+
+type refResolver struct {
+	root   interface{}
+	active map[string]bool
+}
+
+func (r *refResolver) resolve(v interface{}) (interface{}, error) {
+	switch c := expandLazy(v).(type) {
+	case map[string]interface{}:
+		if ref, ok := c["$ref"].(string); ok && strings.HasPrefix(ref, "#") {
+			return r.follow(ref)
+		}
+		m := make(map[string]interface{}, len(c))
+		for k, e := range c {
+			ev, err := r.resolve(e)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = ev
+		}
+		return m, nil
+	case []interface{}:
+		a := make([]interface{}, len(c))
+		for i, e := range c {
+			ev, err := r.resolve(e)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = ev
+		}
+		return a, nil
+	}
+	return v, nil
+}
+
+func (r *refResolver) follow(ref string) (interface{}, error) {
+	if r.active[ref] {
+		return nil, fmt.Errorf("circular $ref %q", ref)
+	}
+	fragment, err := url.PathUnescape(ref[1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid $ref %q: %v", ref, err)
+	}
+	tokens, err := parsePointer(fragment)
+	if err != nil {
+		return nil, fmt.Errorf("invalid $ref %q: %v", ref, err)
+	}
+	target, ok := pointerGet(r.root, tokens)
+	if !ok {
+		return nil, fmt.Errorf("unresolvable $ref %q", ref)
+	}
+	r.active[ref] = true
+	defer delete(r.active, ref)
+	return r.resolve(target)
+}
@@ -0,0 +1,56 @@
+package gson
+
+import (
+	"git.egret.io/go/assert"
+	"testing"
+)
+
+func TestResolveRefs(t *testing.T) {
+	js, err := NewGson([]byte(`{
+		"definitions": {
+			"id": {"type": "integer"},
+			"user": {"type": "object", "properties": {"id": {"$ref": "#/definitions/id"}}},
+			"a~b/c": {"type": "string"}
+		},
+		"paths": [{"$ref": "#/definitions/user", "ignored": true}, {"$ref": "#/definitions/a~0b~1c"}],
+		"remote": {"$ref": "other.json#/x"}
+	}`))
+	assert.Equal(t, nil, err)
+
+	resolved, err := js.ResolveRefs()
+	assert.Equal(t, nil, err)
+	b, _ := resolved.GetPath("paths").Encode()
+	assert.Equal(t, `[{"properties":{"id":{"type":"integer"}},"type":"object"},{"type":"string"}]`, string(b))
+	assert.Equal(t, "other.json#/x", resolved.GetPath("remote", "$ref").MustString())
+
+	// the original still holds the references
+	assert.Equal(t, "#/definitions/id", js.GetPath("definitions", "user", "properties", "id", "$ref").MustString())
+
+	// the resolved copies don't share data
+	resolved.GetPath("paths").GetIndex(0).Set("type", "changed")
+	assert.Equal(t, "object", resolved.GetPath("definitions", "user", "type").MustString())
+
+	js, _ = NewGson([]byte(`{"a":{"next":{"$ref":"#/b"}},"b":{"next":{"$ref":"#/a"}}}`))
+	_, err = js.ResolveRefs()
+	assert.NotEqual(t, nil, err)
+
+	js, _ = NewGson([]byte(`{"self":{"$ref":"#"}}`))
+	_, err = js.ResolveRefs()
+	assert.Equal(t, `circular $ref "#"`, err.Error())
+
+	js, _ = NewGson([]byte(`{"a":{"$ref":"#/missing"}}`))
+	_, err = js.ResolveRefs()
+	assert.Equal(t, `unresolvable $ref "#/missing"`, err.Error())
+
+	// the same node referenced twice is not a cycle
+	js, _ = NewGson([]byte(`{"x":1,"a":[{"$ref":"#/x"},{"$ref":"#/x"}]}`))
+	resolved, err = js.ResolveRefs()
+	assert.Equal(t, nil, err)
+	b, _ = resolved.Get("a").Encode()
+	assert.Equal(t, `[1,1]`, string(b))
+
+	lazy, _ := NewLazy([]byte(`{"defs":{"port":{"n":80}},"svc":{"port":{"$ref":"#/defs/port"}}}`))
+	resolved, err = lazy.ResolveRefs()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 80, resolved.GetPath("svc", "port", "n").MustInt())
+}
@@ -0,0 +1,99 @@
+package gson
+
+// FindPaths walks the document depth first (the root included, with an
+// empty path) and returns the path of every node for which `pred` returns
+// true. Array elements appear in paths as their decimal index.
+//
+//	js.FindPaths(func(path []string, v *Gson) bool {
+//		return cardRegexp.MatchString(v.MustString())
+//	})
+func (self *Gson) FindPaths(pred func(path []string, value *Gson) bool) [][]string {
+	var found [][]string
+	walk(nil, self.data, func(path []string, v interface{}) bool {
+		if pred(path, self.child(v)) {
+			found = append(found, append([]string{}, path...))
+		}
+		return true
+	})
+	return found
+}
+
+// FindFirst is like FindPaths but stops at the first node for which
+// `pred` returns true, reporting its path and value. `found` is false
+// when no node matches.
+//
+//	path, v, ok := js.FindFirst(func(_ []string, v *Gson) bool {
+//		return v.Get("role").MustString() == "admin"
+//	})
+func (self *Gson) FindFirst(pred func(path []string, value *Gson) bool) (path []string, value *Gson, found bool) {
+	walk(nil, self.data, func(p []string, v interface{}) bool {
+		g := self.child(v)
+		if !pred(p, g) {
+			return true
+		}
+		path, value, found = append([]string{}, p...), g, true
+		return false
+	})
+	return path, value, found
+}
+
+// CountKey returns how many object members named `key` appear anywhere in
+// the document, at any depth
+func (self *Gson) CountKey(key string) int {
+	n := 0
+	walk(nil, self.data, func(_ []string, v interface{}) bool {
+		if m, ok := v.(map[string]interface{}); ok {
+			if _, ok := m[key]; ok {
+				n++
+			}
+		}
+		return true
+	})
+	return n
+}
+
+// CollectKey returns, in depth first order, the value of every object
+// member named `key` anywhere in the document, each wrapped in a `Gson`
+// object
+//
+// useful for pulling e.g. every "id" out of a nested structure:
+//
+//	for _, id := range js.CollectKey("id") {
+//		ids = append(ids, id.MustString())
+//	}
+func (self *Gson) CollectKey(key string) []*Gson {
+	var found []*Gson
+	walk(nil, self.data, func(_ []string, v interface{}) bool {
+		if m, ok := v.(map[string]interface{}); ok {
+			if _, ok := m[key]; ok {
+				found = append(found, self.child(expandMember(m, key)))
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// Leaves returns every scalar value in the document (strings, numbers,
+// booleans and nulls, but not empty objects or arrays) in depth first
+// order, each wrapped in a `Gson` object
+//
+// useful for quick scans that don't care where a value sits:
+//
+//	for _, leaf := range js.Leaves() {
+//		if strings.Contains(leaf.AsString(), "secret") {
+//			return true
+//		}
+//	}
+func (self *Gson) Leaves() []*Gson {
+	var found []*Gson
+	walk(nil, self.data, func(_ []string, v interface{}) bool {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+		default:
+			found = append(found, self.child(v))
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,163 @@
+package gson
+
+import (
+	"encoding/json"
+	"git.egret.io/go/assert"
+	"strings"
+	"testing"
+)
+
+func TestFindPaths(t *testing.T) {
+	js, err := NewGson([]byte(`{
+		"user": {"card": "4111-1111", "name": "ann"},
+		"payments": [{"card": "5500-0000"}, {"note": "none"}]
+	}`))
+	assert.Equal(t, nil, err)
+
+	paths := js.FindPaths(func(path []string, v *Gson) bool {
+		return strings.Contains(v.MustString(), "-")
+	})
+	assert.Equal(t, [][]string{
+		{"payments", "0", "card"},
+		{"user", "card"},
+	}, paths)
+
+	roots := js.FindPaths(func(path []string, v *Gson) bool {
+		return len(path) == 0
+	})
+	assert.Equal(t, [][]string{{}}, roots)
+
+	var none [][]string
+	assert.Equal(t, none, js.FindPaths(func([]string, *Gson) bool { return false }))
+
+	lazy, _ := NewLazy([]byte(`{"user":{"card":"4111-1111"},"payments":[{"card":"5500-0000"}]}`))
+	assert.Equal(t, [][]string{
+		{"payments", "0", "card"},
+		{"user", "card"},
+	}, lazy.FindPaths(func(path []string, v *Gson) bool {
+		return strings.Contains(v.MustString(), "-")
+	}))
+}
+
+func TestFindFirst(t *testing.T) {
+	js, err := NewGson([]byte(`{
+		"user": {"card": "4111-1111", "name": "ann"},
+		"payments": [{"card": "5500-0000"}, {"note": "none"}]
+	}`))
+	assert.Equal(t, nil, err)
+
+	visited := 0
+	path, v, ok := js.FindFirst(func(path []string, v *Gson) bool {
+		visited++
+		return strings.Contains(v.MustString(), "-")
+	})
+	assert.Equal(t, true, ok)
+	assert.Equal(t, []string{"payments", "0", "card"}, path)
+	assert.Equal(t, "5500-0000", v.MustString())
+	// root, payments, payments/0, payments/0/card
+	assert.Equal(t, 4, visited)
+
+	path, v, ok = js.FindFirst(func(path []string, v *Gson) bool {
+		return true
+	})
+	assert.Equal(t, true, ok)
+	assert.Equal(t, []string{}, path)
+	assert.Equal(t, 2, len(v.MustMap()))
+
+	path, v, ok = js.FindFirst(func([]string, *Gson) bool { return false })
+	assert.Equal(t, false, ok)
+	assert.Equal(t, 0, len(path))
+	assert.Equal(t, true, v == nil)
+
+	lazy, _ := NewLazy([]byte(`{"a":{"b":1}}`))
+	path, v, ok = lazy.FindFirst(func(path []string, v *Gson) bool {
+		return len(path) == 2
+	})
+	assert.Equal(t, true, ok)
+	assert.Equal(t, []string{"a", "b"}, path)
+	assert.Equal(t, 1, v.MustInt())
+
+	// the match stays lazy below the levels the walk reached
+	lazy, _ = NewLazy([]byte(`{"a":{"b":{"c":{"d":1}}}}`))
+	_, v, ok = lazy.FindFirst(func(path []string, v *Gson) bool {
+		return len(path) == 1
+	})
+	assert.Equal(t, true, ok)
+	assert.Equal(t, 1, v.GetPath("b", "c", "d").MustInt())
+}
+
+func TestCountKey(t *testing.T) {
+	js, err := NewGson([]byte(`{
+		"error": null,
+		"items": [{"error": "a"}, {"ok": true}, {"nested": {"error": {"error": 1}}}],
+		"0": "not an index"
+	}`))
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, 4, js.CountKey("error"))
+	assert.Equal(t, 1, js.CountKey("ok"))
+	assert.Equal(t, 1, js.CountKey("0"))
+	assert.Equal(t, 0, js.CountKey("missing"))
+	assert.Equal(t, 0, js.Get("error").CountKey("error"))
+
+	lazy, _ := NewLazy([]byte(`{"a":{"error":1,"b":[{"error":2}]}}`))
+	assert.Equal(t, 2, lazy.CountKey("error"))
+}
+
+func TestCollectKey(t *testing.T) {
+	js, err := NewGson([]byte(`{
+		"id": "root",
+		"children": [{"id": "a", "children": [{"id": "b"}]}, {"name": "none"}],
+		"meta": {"id": {"id": "inner"}}
+	}`))
+	assert.Equal(t, nil, err)
+
+	ids := js.CollectKey("id")
+	var strs []string
+	for _, id := range ids {
+		strs = append(strs, id.AsString())
+	}
+	assert.Equal(t, []string{"root", "a", "b", `{"id":"inner"}`, "inner"}, strs)
+
+	// collected objects are shared with the document
+	ids[3].Set("id", "changed")
+	assert.Equal(t, "changed", js.GetPath("meta", "id", "id").MustString())
+
+	assert.Equal(t, 0, len(js.CollectKey("missing")))
+
+	lazy, _ := NewLazy([]byte(`{"a":{"id":1,"b":[{"id":{"n":2}}]}}`))
+	lazyIDs := lazy.CollectKey("id")
+	assert.Equal(t, 2, len(lazyIDs))
+	assert.Equal(t, 1, lazyIDs[0].MustInt())
+	assert.Equal(t, 2, lazyIDs[1].Get("n").MustInt())
+
+	// results keep the document's navigation mode
+	linked, _ := NewGsonLinked([]byte(`{"a":{"id":1}}`))
+	assert.Equal(t, linked, linked.CollectKey("id")[0].Parent())
+}
+
+func TestLeaves(t *testing.T) {
+	js, err := NewGson([]byte(`{"b":[1,{"x":"deep"},[]],"a":"first","c":{"n":null,"t":true,"e":{}}}`))
+	assert.Equal(t, nil, err)
+
+	var values []interface{}
+	for _, leaf := range js.Leaves() {
+		values = append(values, leaf.Interface())
+	}
+	assert.Equal(t, []interface{}{"first", json.Number("1"), "deep", nil, true}, values)
+
+	scalar, _ := NewGson([]byte(`"only"`))
+	leaves := scalar.Leaves()
+	assert.Equal(t, 1, len(leaves))
+	assert.Equal(t, "only", leaves[0].MustString())
+
+	empty, _ := NewGson([]byte(`{}`))
+	assert.Equal(t, 0, len(empty.Leaves()))
+
+	lazy, _ := NewLazy([]byte(`{"a":{"b":[1,"x"]},"c":true}`))
+	var lazyLeaves []string
+	for _, leaf := range lazy.Leaves() {
+		lazyLeaves = append(lazyLeaves, leaf.AsString())
+	}
+	assert.Equal(t, []string{"1", "x", "true"}, lazyLeaves)
+}
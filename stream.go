@@ -0,0 +1,133 @@
+package gson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Indent reads JSON values from `r` and writes each of them to `w`
+// re-indented as json.Indent would, one value per line, without building a
+// `Gson` tree. Values are processed one at a time so memory use is bounded
+// by the largest single value.
+func Indent(w io.Writer, r io.Reader, prefix, indent string) error {
+	dec := json.NewDecoder(r)
+	var raw json.RawMessage
+	var buf bytes.Buffer
+	for {
+		err := dec.Decode(&raw)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		buf.Reset()
+		if err := json.Indent(&buf, raw, prefix, indent); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		if _, err := buf.WriteTo(w); err != nil {
+			return err
+		}
+	}
+}
+
+// NewFromStream decodes every JSON value in `r`, where values follow each
+// other separated only by optional whitespace and may each span several
+// lines, and returns them in order. On a decoding error the values read
+// before it are returned along with the error.
+func NewFromStream(r io.Reader) ([]*Gson, error) {
+	var out []*Gson
+	err := EachFromStream(r, func(js *Gson) error {
+		out = append(out, js)
+		return nil
+	})
+	return out, err
+}
+
+// EachFromStream is like NewFromStream but hands each value to `fn` as soon
+// as it has been decoded instead of collecting them. An error returned by
+// `fn` stops the stream and is returned as is.
+//
+//	err := gson.EachFromStream(resp.Body, func(event *gson.Gson) error {
+//		return handle(event.Get("type").MustString(), event)
+//	})
+func EachFromStream(r io.Reader, fn func(*Gson) error) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	for {
+		js := new(Gson)
+		err := dec.Decode(&js.data)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(js); err != nil {
+			return err
+		}
+	}
+}
+
+// TransformNDJSON reads newline-delimited JSON from `r`, one value per
+// line, hands each value to `fn` and writes what it returns to `w`, again
+// one value per line. Records are processed one at a time, so memory use
+// is bounded by the largest single line. Blank lines are skipped; `fn`
+// returning nil drops the record, and an error from `fn` or a line that
+// isn't valid JSON stops the transform. Errors are prefixed with the
+// 1-based line number.
+//
+//	err := gson.TransformNDJSON(in, out, func(rec *gson.Gson) (*gson.Gson, error) {
+//		if rec.Get("level").MustString() == "debug" {
+//			return nil, nil
+//		}
+//		rec.Del("host")
+//		return rec, nil
+//	})
+func TransformNDJSON(r io.Reader, w io.Writer, fn func(*Gson) (*Gson, error)) error {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	for n := 1; ; n++ {
+		line, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if len(bytes.TrimSpace(line)) > 0 {
+			if err := transformLine(bw, line, fn); err != nil {
+				return fmt.Errorf("line %d: %w", n, err)
+			}
+		}
+		if err == io.EOF {
+			return bw.Flush()
+		}
+	}
+}
+
+func transformLine(w *bufio.Writer, line []byte, fn func(*Gson) (*Gson, error)) error {
+	in := &Gson{}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&in.data); err != nil {
+		return err
+	}
+	// a line holds exactly one value
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("unexpected data after JSON value")
+	}
+	out, err := fn(in)
+	if err != nil || out == nil {
+		return err
+	}
+	b, err := out.Encode()
+	if err != nil {
+		return err
+	}
+	w.Write(b)
+	return w.WriteByte('\n')
+}
@@ -0,0 +1,122 @@
+package gson
+
+import (
+	"bytes"
+	"errors"
+	"git.egret.io/go/assert"
+	"strings"
+	"testing"
+)
+
+func TestIndent(t *testing.T) {
+	var out bytes.Buffer
+	err := Indent(&out, strings.NewReader(`{"a":[1,2],"b":{}} [true]`), "", "  ")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "{\n  \"a\": [\n    1,\n    2\n  ],\n  \"b\": {}\n}\n[\n  true\n]\n", out.String())
+
+	out.Reset()
+	assert.Equal(t, nil, Indent(&out, strings.NewReader(""), "", "\t"))
+	assert.Equal(t, "", out.String())
+
+	err = Indent(&out, strings.NewReader(`{"a":`), "", "\t")
+	assert.NotEqual(t, nil, err)
+}
+
+func TestNewFromStream(t *testing.T) {
+	input := `{"id": 1,
+  "tags": ["a"]}
+	[1, 2]{"id":12345678901234567890}"str" 3
+null`
+	values, err := NewFromStream(strings.NewReader(input))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 6, len(values))
+	assert.Equal(t, "a", values[0].Get("tags").GetIndex(0).MustString())
+	assert.Equal(t, 2, len(values[1].MustArray()))
+	assert.Equal(t, "12345678901234567890", values[2].Get("id").AsString())
+	assert.Equal(t, "str", values[3].MustString())
+	assert.Equal(t, 3, values[4].MustInt())
+	assert.Equal(t, nil, values[5].Interface())
+
+	values, err = NewFromStream(strings.NewReader(""))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(values))
+
+	values, err = NewFromStream(strings.NewReader(`{"a":1} {"b":`))
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, 1, len(values))
+}
+
+func TestEachFromStream(t *testing.T) {
+	var ids []int
+	err := EachFromStream(strings.NewReader(`{"id":1}{"id":2} {"id":3}`), func(js *Gson) error {
+		ids = append(ids, js.Get("id").MustInt())
+		return nil
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int{1, 2, 3}, ids)
+
+	stop := errors.New("stop")
+	ids = nil
+	err = EachFromStream(strings.NewReader(`{"id":1}{"id":2}{"id":3}`), func(js *Gson) error {
+		ids = append(ids, js.Get("id").MustInt())
+		if len(ids) == 2 {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, []int{1, 2}, ids)
+}
+
+func TestTransformNDJSON(t *testing.T) {
+	input := "{\"level\":\"info\",\"msg\":\"a\",\"host\":\"h1\"}\n" +
+		"{\"level\":\"debug\",\"msg\":\"b\"}\n" +
+		"\n" +
+		"{\"level\":\"warn\",\"msg\":\"c\",\"id\":12345678901234567890}"
+
+	var out bytes.Buffer
+	err := TransformNDJSON(strings.NewReader(input), &out, func(rec *Gson) (*Gson, error) {
+		if rec.Get("level").MustString() == "debug" {
+			return nil, nil
+		}
+		rec.Del("host")
+		return rec, nil
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "{\"level\":\"info\",\"msg\":\"a\"}\n{\"id\":12345678901234567890,\"level\":\"warn\",\"msg\":\"c\"}\n", out.String())
+
+	out.Reset()
+	err = TransformNDJSON(strings.NewReader("{\"ok\":1}\n{bad\n{\"ok\":2}\n"), &out, func(rec *Gson) (*Gson, error) {
+		return rec, nil
+	})
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, true, strings.HasPrefix(err.Error(), "line 2: "))
+
+	stop := errors.New("stop")
+	err = TransformNDJSON(strings.NewReader("1\n2\n3\n"), &out, func(rec *Gson) (*Gson, error) {
+		if rec.MustInt() == 3 {
+			return nil, stop
+		}
+		return rec.Wrap("n"), nil
+	})
+	assert.Equal(t, true, errors.Is(err, stop))
+	assert.Equal(t, "line 3: stop", err.Error())
+
+	out.Reset()
+	assert.Equal(t, nil, TransformNDJSON(strings.NewReader(""), &out, nil))
+	assert.Equal(t, "", out.String())
+
+	for _, line := range []string{"{\"a\":1} garbage\n", "{\"a\":1}{\"b\":2}\n", "1 2\n"} {
+		out.Reset()
+		err = TransformNDJSON(strings.NewReader("{\"ok\":1}\n"+line), &out, func(rec *Gson) (*Gson, error) {
+			return rec, nil
+		})
+		assert.Equal(t, "line 2: unexpected data after JSON value", err.Error(), line)
+	}
+	out.Reset()
+	err = TransformNDJSON(strings.NewReader("  {\"a\":1} \t\r\n"), &out, func(rec *Gson) (*Gson, error) {
+		return rec, nil
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "{\"a\":1}\n", out.String())
+}
@@ -0,0 +1,62 @@
+package gson
+
+import (
+	"fmt"
+)
+
+// navState is shared by every `Gson` object navigated to from a document
+// created by NewStrictNav
+type navState struct {
+	err error
+}
+
+// NewStrictNav is like NewGson but navigation failures are remembered: the
+// first Get or GetIndex anywhere in the document that misses (an absent
+// key, an index out of range, or a lookup on the wrong kind of value) is
+// recorded and returned by Err, naming the failing segment as a JSON
+// Pointer. The methods still return a wrapper around nil as usual, so a
+// whole chain can be written fluently and checked once:
+//
+//	port := js.Get("server").Get("port").MustInt()
+//	if err := js.Err(); err != nil {
+//		return err
+//	}
+//
+// CheckGet, which reports misses itself, records nothing.
+func NewStrictNav(body []byte) (*Gson, error) {
+	self, err := NewGson(body)
+	if err != nil {
+		return nil, err
+	}
+	self.nav = new(navState)
+	return self, nil
+}
+
+// Err returns the first navigation failure recorded on the document since
+// it was created with NewStrictNav; it is the same for every `Gson` object
+// navigated to from it, and always nil for other documents
+func (self *Gson) Err() error {
+	if self.nav == nil {
+		return nil
+	}
+	return self.nav.err
+}
+
+// step wraps the value reached from self through the path segment `seg`
+func (self *Gson) step(seg string, val interface{}) *Gson {
+	c := self.child(val)
+	if self.nav != nil {
+		c.navPath = append(self.navPath[:len(self.navPath):len(self.navPath)], seg)
+	}
+	return c
+}
+
+// fail records that navigating from self through `seg` failed, unless an
+// earlier failure has been recorded already
+func (self *Gson) fail(seg string, format string, args ...interface{}) {
+	if self.nav == nil || self.nav.err != nil {
+		return
+	}
+	at := formatPointer(append(self.navPath[:len(self.navPath):len(self.navPath)], seg))
+	self.nav.err = fmt.Errorf("%s: %s", at, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,46 @@
+package gson
+
+import (
+	"git.egret.io/go/assert"
+	"testing"
+)
+
+func TestNewStrictNav(t *testing.T) {
+	body := []byte(`{"server":{"port":8080,"hosts":["a","b"],"name":"web"}}`)
+
+	js, err := NewStrictNav(body)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 8080, js.Get("server").Get("port").MustInt())
+	assert.Equal(t, "b", js.GetPath("server", "hosts").GetIndex(1).MustString())
+	_, ok := js.Get("server").CheckGet("missing")
+	assert.Equal(t, false, ok)
+	assert.Equal(t, nil, js.Err())
+
+	assert.Equal(t, 0, js.Get("server").Get("tls").Get("port").MustInt())
+	assert.Equal(t, "/server/tls: key not found", js.Err().Error())
+
+	// later failures don't replace the first one
+	js.GetIndex(3)
+	assert.Equal(t, "/server/tls: key not found", js.Get("server").Err().Error())
+
+	for expr, expected := range map[string]string{
+		"index": "/server/hosts/5: index out of range (length 2)",
+		"kind":  "/server/name/0: cannot index string",
+		"array": "/server/hosts/x: cannot look up key in array",
+	} {
+		js, _ := NewStrictNav(body)
+		switch expr {
+		case "index":
+			js.Get("server").Get("hosts").GetIndex(5)
+		case "kind":
+			js.GetPath("server", "name").GetIndex(0).Get("deeper")
+		case "array":
+			js.GetPath("server", "hosts", "x")
+		}
+		assert.Equal(t, expected, js.Err().Error())
+	}
+
+	lenient, _ := NewGson(body)
+	lenient.Get("nope").Get("nope")
+	assert.Equal(t, nil, lenient.Err())
+}
@@ -0,0 +1,58 @@
+package gson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"strings"
+)
+
+// EncodeTOML returns its data marshaled as TOML. TOML constrains what can
+// be expressed:
+//
+//   - the document must be an object, which becomes the root table
+//   - nested objects become tables and arrays of objects arrays of tables;
+//     other arrays, including mixed ones, become inline arrays
+//   - integers must fit in an int64; other numbers become floats
+//   - TOML has no null: null members are omitted, and null array elements
+//     are an error
+//   - keys are emitted sorted, plain values of a table before its sub-tables
+func (self *Gson) EncodeTOML() ([]byte, error) {
+	v, err := jsonValue(self.data)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := v.(map[string]interface{}); !ok {
+		return nil, errors.New("TOML requires an object at the top level")
+	}
+
+	var numErr error
+	v = copyTree(v, func(leaf interface{}) interface{} {
+		n, ok := leaf.(json.Number)
+		if !ok {
+			return leaf
+		}
+		if i, err := n.Int64(); err == nil {
+			return i
+		}
+		if !strings.ContainsAny(n.String(), ".eE") && numErr == nil {
+			numErr = fmt.Errorf("integer %s is out of range for TOML", n)
+		}
+		f, err := n.Float64()
+		if err != nil && numErr == nil {
+			numErr = fmt.Errorf("number %s cannot be encoded as TOML: %w", n, err)
+		}
+		return f
+	})
+	if numErr != nil {
+		return nil, numErr
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,49 @@
+package gson
+
+import (
+	"git.egret.io/go/assert"
+	"testing"
+)
+
+func TestEncodeTOML(t *testing.T) {
+	js, err := NewGson([]byte(`{
+		"title": "cfg",
+		"port": 8080,
+		"ratio": 0.25,
+		"skip": null,
+		"mixed": [1, "two"],
+		"server": {"host": "localhost", "tls": {"enabled": true}},
+		"users": [{"name": "ann"}, {"name": "bob"}]
+	}`))
+	assert.Equal(t, nil, err)
+
+	b, err := js.EncodeTOML()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `mixed = [1, "two"]
+port = 8080
+ratio = 0.25
+title = "cfg"
+
+[server]
+  host = "localhost"
+  [server.tls]
+    enabled = true
+
+[[users]]
+  name = "ann"
+
+[[users]]
+  name = "bob"
+`, string(b))
+
+	for _, body := range []string{
+		`[1, 2]`,
+		`{"big": 9223372036854775808}`,
+		`{"inf": 1e400}`,
+		`{"list": [1, null]}`,
+	} {
+		js, _ := NewGson([]byte(body))
+		_, err := js.EncodeTOML()
+		assert.NotEqual(t, nil, err, body)
+	}
+}
@@ -0,0 +1,213 @@
+package gson
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Skeleton returns a pointer to a new `Gson` object with the same structure
+// as its data (object keys and array lengths) but every scalar replaced by
+// the zero value of its kind: "" for strings, 0 for numbers and false for
+// booleans. Nulls stay null. The result is a data-free template of the
+// document.
+func (self *Gson) Skeleton() *Gson {
+	v, err := jsonValue(self.data)
+	if err != nil {
+		v = self.data
+	}
+	return &Gson{data: copyTree(v, func(leaf interface{}) interface{} {
+		switch leaf.(type) {
+		case string:
+			return ""
+		case bool:
+			return false
+		case nil:
+			return nil
+		}
+		if _, ok := toNumber(leaf); ok {
+			return json.Number("0")
+		}
+		return leaf
+	})}
+}
+
+// CamelizeKeys returns a pointer to a new `Gson` object in which every
+// object key, at any depth, has been converted from snake_case to
+// camelCase: "user_id" becomes "userId". Leading underscores are kept
+// ("_secret_key" becomes "_secretKey"), runs of underscores collapse, and
+// capitals already present are left alone, so acronyms survive
+// ("HTTP_server" becomes "HTTPServer").
+//
+// when two keys of one object convert to the same name, a key that was
+// already in camelCase wins.
+func (self *Gson) CamelizeKeys() *Gson {
+	return &Gson{data: renameKeys(self.data, camelize)}
+}
+
+// SnakeizeKeys returns a pointer to a new `Gson` object in which every
+// object key, at any depth, has been converted from camelCase to
+// snake_case: "userId" becomes "user_id". Runs of capitals are treated as
+// one acronym word ("userID" becomes "user_id", "HTTPServer" becomes
+// "http_server"), and leading underscores are kept.
+//
+// when two keys of one object convert to the same name, a key that was
+// already in snake_case wins.
+func (self *Gson) SnakeizeKeys() *Gson {
+	return &Gson{data: renameKeys(self.data, snakeize)}
+}
+
+// renameKeys returns a copy of `v` with every object key passed through
+// `fn`, decoding subtrees left raw by NewLazy into the copy
+func renameKeys(v interface{}, fn func(string) string) interface{} {
+	switch c := expandLazy(v).(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(c))
+		for _, k := range sortedKeys(c) {
+			nk := fn(k)
+			if _, taken := m[nk]; taken && nk != k {
+				continue
+			}
+			m[nk] = renameKeys(c[k], fn)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(c))
+		for i, e := range c {
+			a[i] = renameKeys(e, fn)
+		}
+		return a
+	}
+	return v
+}
+
+func camelize(s string) string {
+	body := strings.TrimLeft(s, "_")
+	var b strings.Builder
+	b.WriteString(s[:len(s)-len(body)])
+	for i, part := range strings.FieldsFunc(body, func(r rune) bool { return r == '_' }) {
+		if i > 0 {
+			r := []rune(part)
+			r[0] = unicode.ToUpper(r[0])
+			part = string(r)
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+func snakeize(s string) string {
+	body := strings.TrimLeft(s, "_")
+	var b strings.Builder
+	b.WriteString(s[:len(s)-len(body)])
+	r := []rune(body)
+	for i, c := range r {
+		if unicode.IsUpper(c) && i > 0 && r[i-1] != '_' {
+			prev := r[i-1]
+			nextLower := i+1 < len(r) && unicode.IsLower(r[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(c))
+	}
+	return b.String()
+}
+
+// Render returns a pointer to a new `Gson` object in which `{{key}}`
+// placeholders in string values have been filled in from `ctx`.
+//
+// a string that consists of nothing but one placeholder is replaced by the
+// context value itself, so `"{{user}}"` can become an object, an array or a
+// number. Placeholders inside longer strings are substituted as text:
+// strings are inserted verbatim and everything else as its JSON encoding.
+// A placeholder naming a key that `ctx` doesn't hold is an error.
+func (self *Gson) Render(ctx map[string]interface{}) (*Gson, error) {
+	var err error
+	v := copyTree(self.data, func(leaf interface{}) interface{} {
+		s, ok := leaf.(string)
+		if !ok || err != nil {
+			return leaf
+		}
+		if m := wholePlaceholder.FindStringSubmatch(s); m != nil {
+			val, ok := ctx[m[1]]
+			if !ok {
+				err = fmt.Errorf("missing template value %q", m[1])
+				return leaf
+			}
+			if val, err = jsonValue(val); err != nil {
+				err = fmt.Errorf("template value %q: %v", m[1], err)
+			}
+			return val
+		}
+		return placeholder.ReplaceAllStringFunc(s, func(p string) string {
+			key := placeholder.FindStringSubmatch(p)[1]
+			val, ok := ctx[key]
+			if !ok {
+				if err == nil {
+					err = fmt.Errorf("missing template value %q", key)
+				}
+				return p
+			}
+			if str, ok := val.(string); ok {
+				return str
+			}
+			b, e := json.Marshal(val)
+			if e != nil && err == nil {
+				err = fmt.Errorf("template value %q: %v", key, e)
+			}
+			return string(b)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Gson{data: v}, nil
+}
+
+var (
+	placeholder      = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+	wholePlaceholder = regexp.MustCompile(`^\{\{\s*([^{}]*?)\s*\}\}$`)
+)
+
+// TruncateStrings returns a pointer to a new `Gson` object in which every
+// string value longer than `maxLen` runes has been cut down to `maxLen`
+// runes. Lengths are counted in runes rather than bytes, so multibyte
+// characters are never split.
+//
+// the optional `ellipsis` is appended to every string that was cut, within
+// the `maxLen` budget:
+//
+//	js.TruncateStrings(80, "…")
+func (self *Gson) TruncateStrings(maxLen int, ellipsis ...string) *Gson {
+	var suffix []rune
+
+	switch len(ellipsis) {
+	case 0:
+	case 1:
+		suffix = []rune(ellipsis[0])
+	default:
+		log.Panicf("TruncateStrings() received too many arguments %d", len(ellipsis))
+	}
+
+	if maxLen < 0 {
+		maxLen = 0
+	}
+	return &Gson{data: copyTree(self.data, func(leaf interface{}) interface{} {
+		s, ok := leaf.(string)
+		if !ok || len(s) <= maxLen {
+			return leaf
+		}
+		r := []rune(s)
+		if len(r) <= maxLen {
+			return leaf
+		}
+		if len(suffix) >= maxLen {
+			return string(suffix[:maxLen])
+		}
+		return string(r[:maxLen-len(suffix)]) + string(suffix)
+	})}
+}
@@ -0,0 +1,121 @@
+package gson
+
+import (
+	"git.egret.io/go/assert"
+	"testing"
+)
+
+func TestSkeleton(t *testing.T) {
+	js, err := NewGson([]byte(`{"name":"ann","age":31.5,"admin":true,"boss":null,"tags":["a","b"],"address":{"zip":12345}}`))
+	assert.Equal(t, nil, err)
+	js.Set("score", 99)
+
+	b, err := js.Skeleton().Encode()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"address":{"zip":0},"admin":false,"age":0,"boss":null,"name":"","score":0,"tags":["",""]}`, string(b))
+
+	assert.Equal(t, "ann", js.Get("name").MustString())
+}
+
+func TestCamelizeKeys(t *testing.T) {
+	for in, out := range map[string]string{
+		"user_id":      "userId",
+		"_secret_key":  "_secretKey",
+		"a__b_":        "aB",
+		"HTTP_server":  "HTTPServer",
+		"alreadyCamel": "alreadyCamel",
+		"__":           "__",
+		"ünï_côde":     "ünïCôde",
+	} {
+		assert.Equal(t, out, camelize(in), in)
+	}
+
+	js, err := NewGson([]byte(`{"user_id":1,"user_info":{"first_name":"ann","tag_list":[{"tag_name":"x"}]},"userId":2}`))
+	assert.Equal(t, nil, err)
+	b, _ := js.CamelizeKeys().Encode()
+	assert.Equal(t, `{"userId":2,"userInfo":{"firstName":"ann","tagList":[{"tagName":"x"}]}}`, string(b))
+
+	// the original keeps its keys
+	assert.Equal(t, "ann", js.GetPath("user_info", "first_name").MustString())
+
+	lazy, err := NewLazy([]byte(`{"user_info":{"tag_list":[{"tag_name":"x"}]}}`))
+	assert.Equal(t, nil, err)
+	b, _ = lazy.CamelizeKeys().Encode()
+	assert.Equal(t, `{"userInfo":{"tagList":[{"tagName":"x"}]}}`, string(b))
+}
+
+func TestSnakeizeKeys(t *testing.T) {
+	for in, out := range map[string]string{
+		"userId":          "user_id",
+		"userID":          "user_id",
+		"HTTPServer":      "http_server",
+		"getHTTPResponse": "get_http_response",
+		"_privateKey":     "_private_key",
+		"already_snake":   "already_snake",
+		"ID":              "id",
+		"a1B":             "a1_b",
+		"Name":            "name",
+	} {
+		assert.Equal(t, out, snakeize(in), in)
+	}
+
+	js, err := NewGson([]byte(`{"userId":1,"userInfo":{"firstName":"ann","tagList":[{"tagName":"x"}]},"user_id":2}`))
+	assert.Equal(t, nil, err)
+	b, _ := js.SnakeizeKeys().Encode()
+	assert.Equal(t, `{"user_id":2,"user_info":{"first_name":"ann","tag_list":[{"tag_name":"x"}]}}`, string(b))
+}
+
+func TestRender(t *testing.T) {
+	js, err := NewGson([]byte(`{"greeting":"hello {{ name }}, you are {{age}}","owner":"{{user}}","tags":["{{tag}}","x"],"port":"{{port}}","raw":"{{}"}`))
+	assert.Equal(t, nil, err)
+
+	out, err := js.Render(map[string]interface{}{
+		"name": "ann",
+		"age":  31,
+		"user": map[string]interface{}{"id": 7, "roles": []string{"admin"}},
+		"tag":  "go",
+		"port": 8080,
+	})
+	assert.Equal(t, nil, err)
+	b, _ := out.Encode()
+	assert.Equal(t, `{"greeting":"hello ann, you are 31","owner":{"id":7,"roles":["admin"]},"port":8080,"raw":"{{}","tags":["go","x"]}`, string(b))
+	assert.Equal(t, int64(8080), out.Get("port").MustInt64())
+
+	// the template is left untouched
+	assert.Equal(t, "{{user}}", js.Get("owner").MustString())
+
+	_, err = js.Render(map[string]interface{}{"name": "ann"})
+	assert.NotEqual(t, nil, err)
+
+	js, _ = NewGson([]byte(`["a {{missing}} b"]`))
+	_, err = js.Render(map[string]interface{}{})
+	assert.Equal(t, `missing template value "missing"`, err.Error())
+
+	lazy, err := NewLazy([]byte(`{"a":{"b":["{{tag}}"]}}`))
+	assert.Equal(t, nil, err)
+	out, err = lazy.Render(map[string]interface{}{"tag": "go"})
+	assert.Equal(t, nil, err)
+	b, _ = out.Encode()
+	assert.Equal(t, `{"a":{"b":["go"]}}`, string(b))
+}
+
+func TestTruncateStrings(t *testing.T) {
+	js, err := NewGson([]byte(`{"short":"abc","long":"abcdefgh","wide":"héllo wörld","list":["日本語テキスト",1,null]}`))
+	assert.Equal(t, nil, err)
+
+	b, _ := js.TruncateStrings(5).Encode()
+	assert.Equal(t, `{"list":["日本語テキ",1,null],"long":"abcde","short":"abc","wide":"héllo"}`, string(b))
+
+	b, _ = js.TruncateStrings(5, "…").Encode()
+	assert.Equal(t, `{"list":["日本語テ…",1,null],"long":"abcd…","short":"abc","wide":"héll…"}`, string(b))
+
+	b, _ = js.TruncateStrings(2, "...").Get("long").Encode()
+	assert.Equal(t, `".."`, string(b))
+
+	assert.Equal(t, "abcdefgh", js.Get("long").MustString())
+
+	lazy, err := NewLazy([]byte(`{"a":{"list":["abcdefgh"]}}`))
+	assert.Equal(t, nil, err)
+	b, _ = lazy.TruncateStrings(5).Encode()
+	assert.Equal(t, `{"a":{"list":["abcde"]}}`, string(b))
+}
@@ -0,0 +1,169 @@
+package gson
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// rewriteLeaves replaces, in place, every scalar leaf below `v` with the
+// result of `fn` and returns the (possibly replaced) root value. Subtrees
+// left raw by NewLazy are decoded on the way.
+func rewriteLeaves(v interface{}, fn func(interface{}) interface{}) interface{} {
+	switch c := expandLazy(v).(type) {
+	case map[string]interface{}:
+		for k, e := range c {
+			c[k] = rewriteLeaves(e, fn)
+		}
+		return c
+	case []interface{}:
+		for i, e := range c {
+			c[i] = rewriteLeaves(e, fn)
+		}
+		return c
+	}
+	return fn(v)
+}
+
+// copyTree returns a copy of `v` with freshly allocated maps and slices in
+// which every scalar leaf has been replaced by the result of `fn`. Subtrees
+// left raw by NewLazy are decoded into the copy; `v` itself is not touched.
+func copyTree(v interface{}, fn func(interface{}) interface{}) interface{} {
+	switch c := expandLazy(v).(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(c))
+		for k, e := range c {
+			m[k] = copyTree(e, fn)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(c))
+		for i, e := range c {
+			a[i] = copyTree(e, fn)
+		}
+		return a
+	}
+	return fn(v)
+}
+
+// walk calls `fn` for `v` and then, depth first, for every value nested
+// below it, visiting object members in key order. `fn` returning false
+// stops the walk, in which case walk returns false as well.
+//
+// the `path` handed to `fn` is only valid for the duration of the call, and
+// subtrees left raw by NewLazy are expanded in place as they are reached.
+func walk(path []string, v interface{}, fn func(path []string, v interface{}) bool) bool {
+	if !fn(path, v) {
+		return false
+	}
+	switch c := v.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(c) {
+			if !walk(append(path, k), expandMember(c, k), fn) {
+				return false
+			}
+		}
+	case []interface{}:
+		for i := range c {
+			if !walk(append(path, strconv.Itoa(i)), expandElem(c, i), fn) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mergedKeys returns the keys found in either `a` or `b`, sorted
+func mergedKeys(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// deepCopy returns a copy of `v` sharing no maps or slices with it
+func deepCopy(v interface{}) interface{} {
+	return copyTree(v, func(leaf interface{}) interface{} {
+		return leaf
+	})
+}
+
+// formatPointer renders `path` as an RFC 6901 JSON Pointer
+func formatPointer(path []string) string {
+	var b strings.Builder
+	for _, p := range path {
+		b.WriteByte('/')
+		b.WriteString(pointerEscaper.Replace(p))
+	}
+	return b.String()
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// parsePointer splits an RFC 6901 JSON Pointer into its unescaped reference
+// tokens. The empty pointer refers to the whole document.
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if p[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON Pointer %q: must start with /", p)
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = pointerUnescaper.Replace(t)
+	}
+	return tokens, nil
+}
+
+// pointerGet returns the value `tokens` lead to below `v`, expanding in
+// place the subtrees left raw by NewLazy along the way
+func pointerGet(v interface{}, tokens []string) (interface{}, bool) {
+	for _, t := range tokens {
+		switch c := v.(type) {
+		case map[string]interface{}:
+			if _, ok := c[t]; !ok {
+				return nil, false
+			}
+			v = expandMember(c, t)
+		case []interface{}:
+			i, ok := pointerIndex(t)
+			if !ok || i >= len(c) {
+				return nil, false
+			}
+			v = expandElem(c, i)
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// pointerIndex parses the reference token `t` as an array index: decimal
+// digits without leading zeros
+func pointerIndex(t string) (int, bool) {
+	if t == "" || (len(t) > 1 && t[0] == '0') || strings.Trim(t, "0123456789") != "" {
+		return 0, false
+	}
+	i, err := strconv.Atoi(t)
+	return i, err == nil
+}
+
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
@@ -0,0 +1,49 @@
+package gson
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidateUTF8 reports every string value in the document that is not
+// valid UTF-8, each error prefixed with the string's location as a JSON
+// Pointer. Decoded documents are always valid (the decoder substitutes
+// U+FFFD for bad input), so this matters for values added with Set and
+// friends from untrusted sources.
+func (self *Gson) ValidateUTF8() []error {
+	var errs []error
+	walk(nil, self.data, func(path []string, v interface{}) bool {
+		if s, ok := v.(string); ok && !utf8.ValidString(s) {
+			errs = append(errs, fmt.Errorf("%s: string is not valid UTF-8", formatPointer(path)))
+		}
+		return true
+	})
+	return errs
+}
+
+// SanitizeUTF8 returns a pointer to a new `Gson` object in which every
+// byte of a string value that is not part of a valid UTF-8 sequence has
+// been replaced by `replacement`, matching how encoding/json substitutes
+// U+FFFD when marshaling. Valid strings are kept as they are.
+//
+//	clean := js.SanitizeUTF8(utf8.RuneError)
+func (self *Gson) SanitizeUTF8(replacement rune) *Gson {
+	return &Gson{data: copyTree(self.data, func(leaf interface{}) interface{} {
+		s, ok := leaf.(string)
+		if !ok || utf8.ValidString(s) {
+			return leaf
+		}
+		var b strings.Builder
+		for i := 0; i < len(s); {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				b.WriteRune(replacement)
+			} else {
+				b.WriteString(s[i : i+size])
+			}
+			i += size
+		}
+		return b.String()
+	})}
+}
@@ -0,0 +1,62 @@
+package gson
+
+import (
+	"git.egret.io/go/assert"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestValidateUTF8(t *testing.T) {
+	js, err := NewGson([]byte(`{"ok":"héllo","list":["fine"]}`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(js.ValidateUTF8()))
+
+	js.Set("bad", "a\xffb")
+	js.Get("list").MustArray()[0] = "\xc3"
+	js.Set("a/b", map[string]interface{}{"x": "ok\xe2\x82"})
+
+	var msgs []string
+	for _, e := range js.ValidateUTF8() {
+		msgs = append(msgs, e.Error())
+	}
+	assert.Equal(t, []string{
+		"/a~1b/x: string is not valid UTF-8",
+		"/bad: string is not valid UTF-8",
+		"/list/0: string is not valid UTF-8",
+	}, msgs)
+
+	lazy, _ := NewLazy([]byte(`{"a":{"b":["fine"]},"c":{"d":{}}}`))
+	lazy.Edit("c", "d").Set("bad", "\xff")
+	msgs = nil
+	for _, e := range lazy.ValidateUTF8() {
+		msgs = append(msgs, e.Error())
+	}
+	assert.Equal(t, []string{"/c/d/bad: string is not valid UTF-8"}, msgs)
+	assert.Equal(t, "fine", lazy.GetPath("a", "b").GetIndex(0).MustString())
+}
+
+func TestSanitizeUTF8(t *testing.T) {
+	js := New()
+	js.Set("bad", "a\xff\xfeb")
+	js.Set("truncated", "caf\xc3")
+	js.Set("good", "日本")
+	js.Set("list", []interface{}{"\x80", 1})
+
+	clean := js.SanitizeUTF8('?')
+	assert.Equal(t, "a??b", clean.Get("bad").MustString())
+	assert.Equal(t, "caf?", clean.Get("truncated").MustString())
+	assert.Equal(t, "日本", clean.Get("good").MustString())
+	assert.Equal(t, "?", clean.Get("list").GetIndex(0).MustString())
+	assert.Equal(t, 0, len(clean.ValidateUTF8()))
+
+	assert.Equal(t, "a��b", js.SanitizeUTF8(utf8.RuneError).Get("bad").MustString())
+
+	// the original is left alone
+	assert.Equal(t, "a\xff\xfeb", js.Get("bad").MustString())
+
+	lazy, err := NewLazy([]byte(`{"a":{"list":["ok"]}}`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[string]interface{}{
+		"a": map[string]interface{}{"list": []interface{}{"ok"}},
+	}, lazy.SanitizeUTF8('?').Interface())
+}
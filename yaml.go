@@ -0,0 +1,92 @@
+package gson
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"strconv"
+)
+
+// NewFromYAML returns a pointer to a new `Gson` object after unmarshaling
+// the YAML document `body`. The result has the same shape NewGson produces:
+// mappings become map[string]interface{} (non-string keys are formatted
+// with fmt), sequences []interface{}, and numbers `json.Number`.
+func NewFromYAML(body []byte) (*Gson, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(body, &v); err != nil {
+		return nil, err
+	}
+	data, err := fromYAML(v)
+	if err != nil {
+		return nil, err
+	}
+	return &Gson{data: data}, nil
+}
+
+func fromYAML(v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, e := range x {
+			val, err := fromYAML(e)
+			if err != nil {
+				return nil, err
+			}
+			if s, ok := k.(string); ok {
+				m[s] = val
+			} else {
+				m[fmt.Sprint(k)] = val
+			}
+		}
+		return m, nil
+	case []interface{}:
+		a := make([]interface{}, len(x))
+		for i, e := range x {
+			val, err := fromYAML(e)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = val
+		}
+		return a, nil
+	case int, int64, uint64, float64:
+		n, ok := toNumber(x)
+		if !ok {
+			return nil, fmt.Errorf("YAML number %v has no JSON representation", x)
+		}
+		return n, nil
+	}
+	return v, nil
+}
+
+// EncodeYAML returns its data marshaled as YAML, with object keys sorted.
+// Numbers are emitted as plain YAML integers or floats, never as quoted
+// strings, and integers keep all their digits up to the uint64 range.
+func (self *Gson) EncodeYAML() ([]byte, error) {
+	v, err := jsonValue(self.data)
+	if err != nil {
+		return nil, err
+	}
+	var numErr error
+	v = copyTree(v, func(leaf interface{}) interface{} {
+		n, ok := leaf.(json.Number)
+		if !ok {
+			return leaf
+		}
+		if i, err := n.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+			return u
+		}
+		f, err := n.Float64()
+		if err != nil && numErr == nil {
+			numErr = fmt.Errorf("number %s cannot be encoded as YAML: %w", n, err)
+		}
+		return f
+	})
+	if numErr != nil {
+		return nil, numErr
+	}
+	return yaml.Marshal(v)
+}
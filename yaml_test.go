@@ -0,0 +1,75 @@
+package gson
+
+import (
+	"encoding/json"
+	"git.egret.io/go/assert"
+	"testing"
+)
+
+func TestNewFromYAML(t *testing.T) {
+	js, err := NewFromYAML([]byte(`
+name: service
+replicas: 3
+ratio: 0.5
+big: 18446744073709551615
+enabled: true
+nothing: ~
+ports:
+  - 80
+  - name: https
+    port: 443
+1: numeric key
+`))
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, "service", js.Get("name").MustString())
+	assert.Equal(t, json.Number("3"), js.Get("replicas").Interface())
+	assert.Equal(t, json.Number("0.5"), js.Get("ratio").Interface())
+	assert.Equal(t, uint64(18446744073709551615), js.Get("big").MustUint64())
+	assert.Equal(t, true, js.Get("enabled").MustBool())
+	assert.Equal(t, nil, js.Get("nothing").Interface())
+	assert.Equal(t, 80, js.Get("ports").GetIndex(0).MustInt())
+	assert.Equal(t, 443, js.Get("ports").GetIndex(1).Get("port").MustInt())
+	assert.Equal(t, "numeric key", js.Get("1").MustString())
+
+	// the result is interchangeable with parsed JSON
+	fromJSON, _ := NewGson([]byte(`{"a":[1,{"b":"c"}]}`))
+	fromYAML, err := NewFromYAML([]byte("a: [1, {b: c}]"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, fromJSON.Interface(), fromYAML.Interface())
+
+	_, err = NewFromYAML([]byte("x: .inf"))
+	assert.NotEqual(t, nil, err)
+	_, err = NewFromYAML([]byte("a: [1"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestEncodeYAML(t *testing.T) {
+	js, err := NewGson([]byte(`{"name":"svc","replicas":3,"ratio":0.5,"big":18446744073709551615,
+		"labels":{"app":"web"},"ports":[80,{"port":443}],"none":null,"numstr":"42"}`))
+	assert.Equal(t, nil, err)
+
+	b, err := js.EncodeYAML()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `big: 18446744073709551615
+labels:
+  app: web
+name: svc
+none: null
+numstr: "42"
+ports:
+- 80
+- port: 443
+ratio: 0.5
+replicas: 3
+`, string(b))
+
+	// round trips through NewFromYAML
+	back, err := NewFromYAML(b)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, js.Interface(), back.Interface())
+
+	huge, _ := NewGson([]byte(`[1e400]`))
+	_, err = huge.EncodeYAML()
+	assert.NotEqual(t, nil, err)
+}